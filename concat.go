@@ -0,0 +1,243 @@
+package gosmpp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// ConcatReassembly settings for reassembling multipart (concatenated) DeliverSM.
+//
+// Segments are recognized by concatenation IE in UDH (8-bit or 16-bit reference)
+// or by sar_msg_ref_num/sar_total_segments/sar_segment_seqnum TLVs.
+// Segments are responded automatically and not passed to OnPDU, OnAllPDU or OnReceivedPduRequest.
+type ConcatReassembly struct {
+	// OnMessage handles reassembled message once all segments are received.
+	//
+	// It is called on the goroutine reading from SMSC, or by a worker if InboundWorkers is set.
+	OnMessage func(ConcatenatedMessage)
+
+	// OnIncomplete handles segments of a message dropped due to Timeout or MaxPendingParts.
+	//
+	// Handle is optional
+	OnIncomplete func(parts []*pdu.DeliverSM)
+
+	// Timeout is the time, counted from the first received segment,
+	// to wait for the remaining segments of a message.
+	//
+	// Zero duration disables timeout, incomplete messages are only dropped by MaxPendingParts.
+	Timeout time.Duration
+
+	// MaxPendingParts limits number of segments held in memory.
+	// When exceeded, the oldest incomplete message is dropped.
+	//
+	// Zero value means no limit.
+	MaxPendingParts int
+}
+
+// ConcatenatedMessage is a multipart message reassembled from its segments.
+type ConcatenatedMessage struct {
+	SourceAddr pdu.Address
+	DestAddr   pdu.Address
+	Reference  uint16
+
+	// Parts are the original DeliverSM, ordered by segment number.
+	Parts []*pdu.DeliverSM
+}
+
+// GetMessageData returns concatenated binary data of all segments, without UDH.
+func (m *ConcatenatedMessage) GetMessageData() (d []byte) {
+	for _, part := range m.Parts {
+		b, _ := part.Message.GetMessageData()
+		d = append(d, b...)
+	}
+	return
+}
+
+// GetMessage returns concatenated message, each segment decoded with its own encoding.
+func (m *ConcatenatedMessage) GetMessage() (string, error) {
+	var sb strings.Builder
+	for _, part := range m.Parts {
+		st, err := part.Message.GetMessage()
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(st)
+	}
+	return sb.String(), nil
+}
+
+type concatKey struct {
+	source string
+	dest   string
+	ref    uint16
+}
+
+type pendingConcat struct {
+	firstSeen time.Time
+	total     byte
+	parts     map[byte]*pdu.DeliverSM
+}
+
+type reassembler struct {
+	settings ConcatReassembly
+
+	mu           sync.Mutex
+	pending      map[concatKey]*pendingConcat
+	pendingParts int
+}
+
+func newReassembler(settings ConcatReassembly) *reassembler {
+	return &reassembler{
+		settings: settings,
+		pending:  make(map[concatKey]*pendingConcat),
+	}
+}
+
+// sarParams reads sar_* TLVs of a PDU.
+type sarParams interface {
+	GetUint8(tag pdu.Tag) (uint8, bool)
+	GetUint16(tag pdu.Tag) (uint16, bool)
+}
+
+// concatInfo extracts concatenation info from UDH, or SAR TLVs otherwise.
+func concatInfo(message *pdu.ShortMessage, p sarParams) (ref uint16, total, seq byte, ok bool) {
+	if total, seq, ref, ok = message.UDH().GetConcatInfo16(); ok {
+		return
+	}
+
+	if ref, ok = p.GetUint16(pdu.TagSarMsgRefNum); !ok {
+		return
+	}
+	if total, ok = p.GetUint8(pdu.TagSarTotalSegments); !ok {
+		return
+	}
+	seq, ok = p.GetUint8(pdu.TagSarSegmentSeqnum)
+	return
+}
+
+func addressKey(a pdu.Address) string {
+	return fmt.Sprintf("%d/%d/%s", a.Ton(), a.Npi(), a.Address())
+}
+
+// add consumes PDU if it's a segment of multipart message.
+func (r *reassembler) add(p pdu.PDU) (consumed bool) {
+	sm, ok := p.(*pdu.DeliverSM)
+	if !ok {
+		return
+	}
+
+	ref, total, seq, ok := concatInfo(&sm.Message, sm)
+	if !ok || total < 2 || seq < 1 || seq > total {
+		return
+	}
+
+	key := concatKey{
+		source: addressKey(sm.SourceAddr),
+		dest:   addressKey(sm.DestAddr),
+		ref:    ref,
+	}
+
+	var (
+		completed *ConcatenatedMessage
+		dropped   [][]*pdu.DeliverSM
+	)
+
+	r.mu.Lock()
+	pc, found := r.pending[key]
+	if !found || pc.total != total {
+		if found {
+			// same reference reused with different total, previous one is stale
+			dropped = append(dropped, r.remove(key))
+		}
+		pc = &pendingConcat{
+			firstSeen: time.Now(),
+			total:     total,
+			parts:     make(map[byte]*pdu.DeliverSM, total),
+		}
+		r.pending[key] = pc
+	}
+
+	if _, duplicated := pc.parts[seq]; !duplicated {
+		r.pendingParts++
+	}
+	pc.parts[seq] = sm
+
+	if len(pc.parts) == int(total) {
+		parts := r.remove(key)
+		completed = &ConcatenatedMessage{
+			SourceAddr: sm.SourceAddr,
+			DestAddr:   sm.DestAddr,
+			Reference:  ref,
+			Parts:      parts,
+		}
+	} else if r.settings.MaxPendingParts > 0 {
+		for r.pendingParts > r.settings.MaxPendingParts {
+			dropped = append(dropped, r.remove(r.oldest()))
+		}
+	}
+	r.mu.Unlock()
+
+	r.notify(completed, dropped)
+	return true
+}
+
+// expire drops incomplete messages older than Timeout.
+func (r *reassembler) expire(now time.Time) {
+	var dropped [][]*pdu.DeliverSM
+
+	r.mu.Lock()
+	for key, pc := range r.pending {
+		if now.Sub(pc.firstSeen) > r.settings.Timeout {
+			dropped = append(dropped, r.remove(key))
+		}
+	}
+	r.mu.Unlock()
+
+	r.notify(nil, dropped)
+}
+
+func (r *reassembler) notify(completed *ConcatenatedMessage, dropped [][]*pdu.DeliverSM) {
+	if r.settings.OnIncomplete != nil {
+		for _, parts := range dropped {
+			r.settings.OnIncomplete(parts)
+		}
+	}
+	if completed != nil && r.settings.OnMessage != nil {
+		r.settings.OnMessage(*completed)
+	}
+}
+
+// remove pending message and returns its parts ordered by segment number. Lock must be held.
+func (r *reassembler) remove(key concatKey) (parts []*pdu.DeliverSM) {
+	pc := r.pending[key]
+	delete(r.pending, key)
+	r.pendingParts -= len(pc.parts)
+
+	seqs := make([]int, 0, len(pc.parts))
+	for seq := range pc.parts {
+		seqs = append(seqs, int(seq))
+	}
+	sort.Ints(seqs)
+
+	parts = make([]*pdu.DeliverSM, 0, len(seqs))
+	for _, seq := range seqs {
+		parts = append(parts, pc.parts[byte(seq)])
+	}
+	return
+}
+
+// oldest returns key of the earliest started pending message. Lock must be held.
+func (r *reassembler) oldest() (key concatKey) {
+	var first time.Time
+	for k, pc := range r.pending {
+		if first.IsZero() || pc.firstSeen.Before(first) {
+			key, first = k, pc.firstSeen
+		}
+	}
+	return
+}
@@ -0,0 +1,220 @@
+package gosmpp
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newSegment(t *testing.T, source, text string, udh pdu.UDH) *pdu.DeliverSM {
+	p := pdu.NewDeliverSM().(*pdu.DeliverSM)
+	require.NoError(t, p.SourceAddr.SetAddress(source))
+	require.NoError(t, p.DestAddr.SetAddress("8888"))
+	require.NoError(t, p.Message.SetMessageWithEncoding(text, data.GSM7BIT))
+	if udh != nil {
+		p.EsmClass |= data.SM_UDH_GSM
+		p.Message.SetUDH(udh)
+	}
+	return p
+}
+
+func newSarSegment(t *testing.T, source, text string, ref uint16, total, seq byte) *pdu.DeliverSM {
+	p := newSegment(t, source, text, nil)
+	p.RegisterOptionalParam(pdu.Field{Tag: pdu.TagSarMsgRefNum, Data: []byte{byte(ref >> 8), byte(ref)}})
+	p.RegisterOptionalParam(pdu.Field{Tag: pdu.TagSarTotalSegments, Data: []byte{total}})
+	p.RegisterOptionalParam(pdu.Field{Tag: pdu.TagSarSegmentSeqnum, Data: []byte{seq}})
+	return p
+}
+
+func TestReassembler(t *testing.T) {
+	t.Run("UDH", func(t *testing.T) {
+		var messages []ConcatenatedMessage
+		r := newReassembler(ConcatReassembly{
+			OnMessage: func(m ConcatenatedMessage) {
+				messages = append(messages, m)
+			},
+		})
+
+		require.False(t, r.add(newSegment(t, "1234", "single", nil)))
+		require.False(t, r.add(pdu.NewSubmitSM()))
+
+		require.True(t, r.add(newSegment(t, "1234", "world", pdu.UDH{pdu.NewIEConcatMessage(2, 2, 7)})))
+		require.True(t, r.add(newSegment(t, "5678", "other ", pdu.UDH{pdu.NewIEConcatMessage(2, 1, 7)})))
+		require.Empty(t, messages)
+
+		require.True(t, r.add(newSegment(t, "1234", "hello ", pdu.UDH{pdu.NewIEConcatMessage(2, 1, 7)})))
+		require.Len(t, messages, 1)
+		require.Equal(t, uint16(7), messages[0].Reference)
+		require.Equal(t, "1234", messages[0].SourceAddr.Address())
+		require.Len(t, messages[0].Parts, 2)
+
+		message, err := messages[0].GetMessage()
+		require.NoError(t, err)
+		require.Equal(t, "hello world", message)
+		require.Equal(t, []byte("hello world"), messages[0].GetMessageData())
+	})
+
+	t.Run("UDH16bit", func(t *testing.T) {
+		var message string
+		r := newReassembler(ConcatReassembly{
+			OnMessage: func(m ConcatenatedMessage) {
+				message, _ = m.GetMessage()
+			},
+		})
+
+		ie := func(seq byte) pdu.UDH {
+			return pdu.UDH{{ID: data.UDH_CONCAT_MSG_16_BIT_REF, Data: []byte{0x01, 0x02, 2, seq}}}
+		}
+		require.True(t, r.add(newSegment(t, "1234", "b", ie(2))))
+		require.True(t, r.add(newSegment(t, "1234", "a", ie(1))))
+		require.Equal(t, "ab", message)
+	})
+
+	t.Run("SAR", func(t *testing.T) {
+		var message string
+		r := newReassembler(ConcatReassembly{
+			OnMessage: func(m ConcatenatedMessage) {
+				message, _ = m.GetMessage()
+			},
+		})
+
+		require.True(t, r.add(newSarSegment(t, "1234", "c", 300, 3, 3)))
+		require.True(t, r.add(newSarSegment(t, "1234", "a", 300, 3, 1)))
+		require.True(t, r.add(newSarSegment(t, "1234", "b", 300, 3, 2)))
+		require.Equal(t, "abc", message)
+	})
+
+	t.Run("Limits", func(t *testing.T) {
+		var dropped [][]*pdu.DeliverSM
+		r := newReassembler(ConcatReassembly{
+			OnIncomplete: func(parts []*pdu.DeliverSM) {
+				dropped = append(dropped, parts)
+			},
+			Timeout:         time.Minute,
+			MaxPendingParts: 2,
+		})
+
+		require.True(t, r.add(newSegment(t, "1", "a", pdu.UDH{pdu.NewIEConcatMessage(3, 1, 1)})))
+		require.True(t, r.add(newSegment(t, "1", "b", pdu.UDH{pdu.NewIEConcatMessage(3, 2, 1)})))
+		require.True(t, r.add(newSegment(t, "2", "a", pdu.UDH{pdu.NewIEConcatMessage(3, 1, 1)})))
+		require.Len(t, dropped, 1)
+		require.Len(t, dropped[0], 2)
+		require.Equal(t, "1", dropped[0][0].SourceAddr.Address())
+
+		r.expire(time.Now())
+		require.Len(t, dropped, 1)
+
+		r.expire(time.Now().Add(2 * time.Minute))
+		require.Len(t, dropped, 2)
+		require.Empty(t, r.pending)
+		require.Zero(t, r.pendingParts)
+	})
+}
+
+func TestConcatReassemblyReceive(t *testing.T) {
+	client, server := net.Pipe()
+
+	responses := make(chan pdu.PDU, 4)
+	go pipeSMSC(server, func(p pdu.PDU) pdu.PDU {
+		responses <- p
+		return nil
+	})
+
+	messages := make(chan ConcatenatedMessage, 1)
+	trans := newTransceivable(NewConnection(client), Settings{
+		ReadTimeout: 2 * time.Second,
+		OnPDU: func(p pdu.PDU, _ bool) {
+			t.Fatal("segments must not reach OnPDU", p)
+		},
+		ConcatReassembly: &ConcatReassembly{
+			OnMessage: func(m ConcatenatedMessage) {
+				messages <- m
+			},
+			Timeout: time.Second,
+		},
+	}, nil)
+	trans.start()
+	defer func() {
+		_ = trans.Close()
+	}()
+
+	peer := NewConnection(server)
+	for _, seg := range []*pdu.DeliverSM{
+		newSegment(t, "1234", "hello ", pdu.UDH{pdu.NewIEConcatMessage(2, 1, 9)}),
+		newSegment(t, "1234", "world", pdu.UDH{pdu.NewIEConcatMessage(2, 2, 9)}),
+	} {
+		_, err := peer.WritePDU(seg)
+		require.NoError(t, err)
+		require.IsType(t, &pdu.DeliverSMResp{}, <-responses)
+	}
+
+	m := <-messages
+	message, err := m.GetMessage()
+	require.NoError(t, err)
+	require.Equal(t, "hello world", message)
+}
+
+func TestConcatReassemblyInboundWorkers(t *testing.T) {
+	client, server := net.Pipe()
+
+	responses := make(chan pdu.PDU, 4)
+	go pipeSMSC(server, func(p pdu.PDU) pdu.PDU {
+		responses <- p
+		return nil
+	})
+
+	started, release := make(chan struct{}), make(chan struct{})
+	trans := newTransceivable(NewConnection(client), Settings{
+		ReadTimeout: 2 * time.Second,
+		ConcatReassembly: &ConcatReassembly{
+			OnMessage: func(ConcatenatedMessage) {
+				close(started)
+				<-release
+			},
+			Timeout: time.Second,
+		},
+		InboundWorkers: &InboundWorkers{Key: KeyBySourceAddr},
+	}, nil)
+	trans.start()
+	defer func() {
+		_ = trans.Close()
+	}()
+
+	peer := NewConnection(server)
+	for _, seg := range []*pdu.DeliverSM{
+		newSarSegment(t, "1234", "hello ", 9, 2, 1),
+		newSarSegment(t, "1234", "world", 9, 2, 2),
+	} {
+		_, err := peer.WritePDU(seg)
+		require.NoError(t, err)
+	}
+	require.IsType(t, &pdu.DeliverSMResp{}, <-responses)
+	<-started
+
+	// slow OnMessage does not stall reading
+	_, err := peer.WritePDU(pdu.NewEnquireLink())
+	require.NoError(t, err)
+	require.IsType(t, &pdu.EnquireLinkResp{}, <-responses)
+
+	close(release)
+	require.IsType(t, &pdu.DeliverSMResp{}, <-responses)
+}
+
+func TestConcatCleanupInterval(t *testing.T) {
+	client, server := net.Pipe()
+	go pipeSMSC(server, func(pdu.PDU) pdu.PDU { return nil })
+
+	// interval must not be zero
+	trans := newTransceivable(NewConnection(client), Settings{
+		ReadTimeout:      2 * time.Second,
+		ConcatReassembly: &ConcatReassembly{Timeout: 1},
+	}, nil)
+	trans.start()
+	time.Sleep(3 * minConcatCleanupInterval)
+	require.NoError(t, trans.Close())
+}
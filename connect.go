@@ -11,7 +11,11 @@ import (
 var (
 	// NonTLSDialer is non-tls connection dialer.
 	NonTLSDialer = func(addr string) (net.Conn, error) {
-		return net.Dial("tcp", addr)
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			return nil, DialError{Addr: addr, Err: err}
+		}
+		return conn, nil
 	}
 )
 
@@ -35,12 +39,43 @@ func (err BindError) Error() string {
 	return fmt.Sprintf("binding error (%s): %s", err.CommandStatus, err.CommandStatus.Desc())
 }
 
-func newBindRequest(s Auth, bindingType pdu.BindingType, addressRange pdu.AddressRange) (bindReq *pdu.BindRequest) {
+// DialError indicates network failure while connecting to SMSC.
+type DialError struct {
+	Addr string
+	Err  error
+}
+
+func (err DialError) Error() string {
+	return fmt.Sprintf("dialing %s: %v", err.Addr, err.Err)
+}
+
+func (err DialError) Unwrap() error {
+	return err.Err
+}
+
+// HandshakeError indicates TLS handshake failure, e.g. untrusted or mismatched certificate.
+type HandshakeError struct {
+	Addr string
+	Err  error
+}
+
+func (err HandshakeError) Error() string {
+	return fmt.Sprintf("tls handshake with %s: %v", err.Addr, err.Err)
+}
+
+func (err HandshakeError) Unwrap() error {
+	return err.Err
+}
+
+func newBindRequest(s Auth, bindingType pdu.BindingType, addressRange pdu.AddressRange, interfaceVersion byte) (bindReq *pdu.BindRequest) {
 	bindReq = pdu.NewBindRequest(bindingType)
 	bindReq.SystemID = s.SystemID
 	bindReq.Password = s.Password
 	bindReq.SystemType = s.SystemType
 	bindReq.AddressRange = addressRange
+	if interfaceVersion != 0 {
+		bindReq.InterfaceVersion = interfaceVersion
+	}
 	return
 }
 
@@ -55,6 +90,12 @@ type connector struct {
 	auth         Auth
 	bindingType  pdu.BindingType
 	addressRange pdu.AddressRange
+
+	interfaceVersion byte
+	versionFallback  []data.CommandStatusType
+
+	tracer    PDUTracer
+	redaction Redaction
 }
 
 func (c *connector) GetBindType() pdu.BindingType {
@@ -62,18 +103,46 @@ func (c *connector) GetBindType() pdu.BindingType {
 }
 
 func (c *connector) Connect() (conn *Connection, err error) {
-	conn, err = connect(c.dialer, c.auth.SMSC, newBindRequest(c.auth, c.bindingType, c.addressRange))
+	return c.bindTo(c.auth.SMSC)
+}
+
+// bindTo dials SMSC and binds, falling back to SMPP 3.3 if enabled.
+func (c *connector) bindTo(addr string) (conn *Connection, err error) {
+	bindReq := newBindRequest(c.auth, c.bindingType, c.addressRange, c.interfaceVersion)
+	if bindReq.InterfaceVersion == data.SMPP_V33_WIRE {
+		if err = checkSMPP33(bindReq); err != nil {
+			return
+		}
+	}
+
+	if conn, err = c.dialAndBind(addr, bindReq); c.shouldFallback(bindReq, err) {
+		conn, err = c.dialAndBind(addr, newBindRequest(c.auth, c.bindingType, c.addressRange, data.SMPP_V33_WIRE))
+	}
 	return
 }
 
-func connect(dialer Dialer, addr string, bindReq *pdu.BindRequest) (c *Connection, err error) {
-	conn, err := dialer(addr)
+func (c *connector) dialAndBind(addr string, bindReq *pdu.BindRequest) (conn *Connection, err error) {
+	nc, err := c.dialer(addr)
 	if err != nil {
 		return
 	}
 
 	// create wrapped connection
-	c = NewConnection(conn)
+	conn = c.newConnection(nc)
+	conn.endpoint = addr
+	return bind(conn, bindReq)
+}
+
+// newConnection wraps net.Conn, attaching tracer if any.
+func (c *connector) newConnection(conn net.Conn) *Connection {
+	wrapped := NewConnection(conn)
+	wrapped.SetTracer(c.tracer, c.redaction)
+	return wrapped
+}
+
+// bind sends binding request over established connection and waits for bind_resp.
+func bind(wrapped *Connection, bindReq *pdu.BindRequest) (c *Connection, err error) {
+	c, conn := wrapped, wrapped.conn
 
 	// send binding request
 	_, err = c.WritePDU(bindReq)
@@ -89,7 +158,11 @@ func connect(dialer Dialer, addr string, bindReq *pdu.BindRequest) (c *Connectio
 	)
 
 	for {
-		if p, err = pdu.Parse(c); err != nil {
+		if p, err = c.ReadPDU(); isInvalidTLV(p, err) {
+			// malformed optional parameter is dropped, bind does not depend on it
+			err = nil
+		}
+		if err != nil {
 			_ = conn.Close()
 			return
 		}
@@ -105,18 +178,26 @@ func connect(dialer Dialer, addr string, bindReq *pdu.BindRequest) (c *Connectio
 		_ = conn.Close()
 	} else {
 		c.systemID = resp.SystemID
+		c.interfaceVersion = bindReq.InterfaceVersion
+		if version, ok := resp.SCInterfaceVersion(); ok && version < c.interfaceVersion {
+			c.interfaceVersion = version
+		}
 	}
 
 	return
 }
 
 // TXConnector returns a Transmitter (TX) connector.
-func TXConnector(dialer Dialer, auth Auth) Connector {
-	return &connector{
+func TXConnector(dialer Dialer, auth Auth, opts ...connectorOption) Connector {
+	c := &connector{
 		dialer:      dialer,
 		auth:        auth,
 		bindingType: pdu.Transmitter,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // RXConnector returns a Receiver (RX) connector.
@@ -152,3 +233,14 @@ func WithAddressRange(addressRange pdu.AddressRange) connectorOption {
 		c.addressRange = addressRange
 	}
 }
+
+// WithInterfaceVersion sets interface_version of bind request, e.g. data.SMPP_V50.
+// Default is data.SMPP_V34.
+//
+// With data.SMPP_V33_WIRE, optional parameters are dropped from all PDUs sent and
+// PDUs introduced by SMPP 3.4, like data_sm or bind_transceiver, fail with ErrUnsupportedPDU.
+func WithInterfaceVersion(version byte) connectorOption {
+	return func(c *connector) {
+		c.interfaceVersion = version
+	}
+}
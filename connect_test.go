@@ -1,9 +1,14 @@
 package gosmpp
 
 import (
-	"github.com/linxGnu/gosmpp/pdu"
+	"context"
+	"net"
 	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
 
 	"github.com/stretchr/testify/require"
 )
@@ -100,3 +105,99 @@ func TestBindingType(t *testing.T) {
 		require.Equal(t, c.GetBindType(), pdu.Transceiver)
 	})
 }
+
+func TestInterfaceVersion(t *testing.T) {
+	addr := startServer(t, NewServer("GoSMSC", nil, func(*ServerSession) Settings {
+		return Settings{
+			ReadTimeout: 2 * time.Second,
+			OnAllPDU: func(p pdu.PDU) (pdu.PDU, bool) {
+				resp := p.GetResponse()
+				if r, ok := resp.(*pdu.BroadcastSMResp); ok {
+					r.MessageID = "bc-1"
+				}
+				return resp, false
+			},
+		}
+	}))
+	auth := Auth{SMSC: addr, SystemID: "esme"}
+
+	conn, err := TRXConnector(NonTLSDialer, auth).Connect()
+	require.NoError(t, err)
+	require.Equal(t, data.SMPP_V34, conn.InterfaceVersion())
+	_ = conn.Close()
+
+	session, err := NewSession(TRXConnector(NonTLSDialer, auth, WithInterfaceVersion(data.SMPP_V50)),
+		Settings{ReadTimeout: 2 * time.Second}, -1)
+	require.NoError(t, err)
+	defer func() {
+		_ = session.Close()
+	}()
+	require.Equal(t, data.SMPP_V50, session.bound().conn.InterfaceVersion())
+
+	req := pdu.NewBroadcastSM().(*pdu.BroadcastSM)
+	req.AddBroadcastArea(data.BCAST_AREA_FORMAT_ALIAS, []byte("north"))
+	req.SetBroadcastContentType(data.BCAST_NETWORK_GSM, 1)
+	req.SetBroadcastRepNum(1)
+	req.SetBroadcastFrequencyInterval(data.BCAST_FREQ_ASAP, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	resp, err := session.SubmitWithContext(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, "bc-1", resp.(*pdu.BroadcastSMResp).MessageID)
+}
+
+func TestBindInvalidTLV(t *testing.T) {
+	malformed := pdu.Field{Tag: pdu.TagSourcePort, Data: []byte{0x01}}
+
+	t.Run("BindResp", func(t *testing.T) {
+		client, server := net.Pipe()
+		go func() {
+			c := NewConnection(server)
+			p, err := c.ReadPDU()
+			if err != nil {
+				return
+			}
+			resp := p.GetResponse().(*pdu.BindResp)
+			resp.SystemID = "GoSMSC"
+			resp.RegisterOptionalParam(malformed)
+			_, _ = c.WritePDU(resp)
+		}()
+
+		req := pdu.NewBindRequest(pdu.Transceiver)
+		req.SystemID = "esme"
+		conn, err := bind(NewConnection(client), req)
+		require.NoError(t, err)
+		require.Equal(t, "GoSMSC", conn.systemID)
+		_ = conn.Close()
+	})
+
+	t.Run("BindRequest", func(t *testing.T) {
+		addr := startServer(t, newTestServer())
+		conn, err := NonTLSDialer(addr)
+		require.NoError(t, err)
+
+		req := pdu.NewBindRequest(pdu.Transceiver)
+		req.SystemID, req.Password = "esme", "secret"
+		req.RegisterOptionalParam(malformed)
+		c, err := bind(NewConnection(conn), req)
+		require.NoError(t, err)
+		_ = c.Close()
+	})
+
+	t.Run("Outbind", func(t *testing.T) {
+		client, server := net.Pipe()
+		defer func() {
+			_ = client.Close()
+		}()
+		go func() {
+			outbind := pdu.NewOutbind().(*pdu.Outbind)
+			outbind.SystemID, outbind.Password = "esme", "secret"
+			outbind.RegisterOptionalParam(malformed)
+			_, _ = NewConnection(server).WritePDU(outbind)
+		}()
+
+		c := &OutbindConnector{connector: connector{auth: Auth{SystemID: "esme", Password: "secret"}}}
+		require.NoError(t, c.acceptOutbind(NewConnection(client)))
+	})
+}
@@ -2,17 +2,24 @@ package gosmpp
 
 import (
 	"bufio"
+	"errors"
 	"net"
 	"time"
 
+	"github.com/linxGnu/gosmpp/data"
 	"github.com/linxGnu/gosmpp/pdu"
 )
 
 // Connection wraps over net.Conn with buffered data reader.
 type Connection struct {
-	systemID string
-	conn     net.Conn
-	reader   *bufio.Reader
+	systemID         string
+	endpoint         string
+	interfaceVersion byte
+	conn             net.Conn
+	reader           *bufio.Reader
+
+	tracer    PDUTracer
+	redaction Redaction
 }
 
 // NewConnection returns a Connection.
@@ -40,14 +47,62 @@ func (c *Connection) Write(b []byte) (n int, err error) {
 	return
 }
 
+// Endpoint returns SMSC address the connection was dialed to by Connector,
+// remote address for accepted connections.
+func (c *Connection) Endpoint() string {
+	if c.endpoint == "" && c.conn.RemoteAddr() != nil {
+		return c.conn.RemoteAddr().String()
+	}
+	return c.endpoint
+}
+
+// InterfaceVersion returns SMPP version of the bind: interface_version of bind request,
+// lowered to sc_interface_version returned by SMSC supporting an older version only.
+func (c *Connection) InterfaceVersion() byte {
+	return c.interfaceVersion
+}
+
+// SetTracer sets tracer recording every PDU read and written through ReadPDU and WritePDU.
+// Nil tracer disables tracing.
+func (c *Connection) SetTracer(tracer PDUTracer, redaction Redaction) {
+	c.tracer, c.redaction = tracer, redaction
+}
+
 // WritePDU data to the connection.
 func (c *Connection) WritePDU(p pdu.PDU) (n int, err error) {
 	buf := pdu.NewBuffer(make([]byte, 0, 64))
+	buf.OmitOptionalParams = c.interfaceVersion == data.SMPP_V33_WIRE
 	p.Marshal(buf)
 	n, err = c.conn.Write(buf.Bytes())
+
+	if c.tracer != nil {
+		trace := newPDUTrace(p, buf, c.redaction)
+		trace.Err = err
+		c.tracer.TracePDU(trace)
+	}
 	return
 }
 
+// ReadPDU reads and parses PDU from the connection.
+func (c *Connection) ReadPDU() (p pdu.PDU, err error) {
+	var frame []byte
+	if frame, err = pdu.ReadFrame(c); err == nil {
+		p, err = pdu.ParseFrame(frame)
+	}
+
+	// frames failed to parse are traced too, header is read at least
+	if c.tracer != nil && len(frame) >= data.PDU_HEADER_SIZE {
+		c.tracer.TracePDU(newInboundTrace(frame, p, err, c.redaction))
+	}
+	return
+}
+
+// isInvalidTLV checks if PDU was read completely, only its malformed optional parameter is dropped.
+func isInvalidTLV(p pdu.PDU, err error) bool {
+	var tlvErr *pdu.InvalidTLVError
+	return p != nil && errors.As(err, &tlvErr)
+}
+
 // Close closes the connection.
 // Any blocked Read or Write operations will be unblocked and return errors.
 func (c *Connection) Close() error {
@@ -107,10 +107,29 @@ func _() {
 	_ = x[ESME_RINVOPTPARAMVAL-196]
 	_ = x[ESME_RDELIVERYFAILURE-254]
 	_ = x[ESME_RUNKNOWNERR-255]
+	_ = x[ESME_RSERTYPUNAUTH-256]
+	_ = x[ESME_RPROHIBITED-257]
+	_ = x[ESME_RSERTYPUNAVAIL-258]
+	_ = x[ESME_RSERTYPDENIED-259]
+	_ = x[ESME_RINVDCS-260]
+	_ = x[ESME_RINVSRCADDRSUBUNIT-261]
+	_ = x[ESME_RINVDSTADDRSUBUNIT-262]
+	_ = x[ESME_RINVBCASTFREQINT-263]
+	_ = x[ESME_RINVBCASTALIAS_NAME-264]
+	_ = x[ESME_RINVBCASTAREAFMT-265]
+	_ = x[ESME_RINVNUMBCAST_AREAS-266]
+	_ = x[ESME_RINVBCASTCNTTYPE-267]
+	_ = x[ESME_RINVBCASTMSGCLASS-268]
+	_ = x[ESME_RBCASTFAIL-269]
+	_ = x[ESME_RBCASTQUERYFAIL-270]
+	_ = x[ESME_RBCASTCANCELFAIL-271]
+	_ = x[ESME_RINVBCAST_REP-272]
+	_ = x[ESME_RINVBCASTSRVGRP-273]
+	_ = x[ESME_RINVBCASTCHANIND-274]
 	_ = x[ESME_LAST_ERROR-300]
 }
 
-const _CommandStatusType_name = "ESME_ROKESME_RINVMSGLENESME_RINVCMDLENESME_RINVCMDIDESME_RINVBNDSTSESME_RALYBNDESME_RINVPRTFLGESME_RINVREGDLVFLGESME_RSYSERRESME_RINVSRCADRESME_RINVDSTADRESME_RINVMSGIDESME_RBINDFAILESME_RINVPASWDESME_RINVSYSIDESME_RCANCELFAILESME_RREPLACEFAILESME_RMSGQFULESME_RINVSERTYPESME_RADDCUSTFAILESME_RDELCUSTFAILESME_RMODCUSTFAILESME_RENQCUSTFAILESME_RINVCUSTIDESME_RINVCUSTNAMEESME_RINVCUSTADRESME_RINVADRESME_RCUSTEXISTESME_RCUSTNOTEXISTESME_RADDDLFAILESME_RMODDLFAILESME_RDELDLFAILESME_RVIEWDLFAILESME_RLISTDLSFAILESME_RPARAMRETFAILESME_RINVPARAMESME_RINVNUMDESTSESME_RINVDLNAMEESME_RINVDLMEMBDESCESME_RINVDLMEMBTYPESME_RINVDLMODOPTESME_RINVDESTFLAGESME_RINVSUBREPESME_RINVESMCLASSESME_RCNTSUBDLESME_RSUBMITFAILESME_RINVSRCTONESME_RINVSRCNPIESME_RINVDSTTONESME_RINVDSTNPIESME_RINVSYSTYPESME_RINVREPFLAGESME_RINVNUMMSGSESME_RTHROTTLEDESME_RPROVNOTALLWDESME_RINVSCHEDESME_RINVEXPIRYESME_RINVDFTMSGIDESME_RX_T_APPNESME_RX_P_APPNESME_RX_R_APPNESME_RQUERYFAILESME_RINVPGCUSTIDESME_RINVPGCUSTIDLENESME_RINVCITYLENESME_RINVSTATELENESME_RINVZIPPREFIXLENESME_RINVZIPPOSTFIXLENESME_RINVMINLENESME_RINVMINESME_RINVPINLENESME_RINVTERMCODELENESME_RINVCHANNELLENESME_RINVCOVREGIONLENESME_RINVCAPCODELENESME_RINVMDTLENESME_RINVPRIORMSGLENESME_RINVPERMSGLENESME_RINVPGALERTLENESME_RINVSMUSERLENESME_RINVRTDBLENESME_RINVREGDELLENESME_RINVMSGDISTLENESME_RINVPRIORMSGESME_RINVMDTESME_RINVPERMSGESME_RINVMSGDISTESME_RINVPGALERTESME_RINVSMUSERESME_RINVRTDBESME_RINVREGDELESME_RINVOPTPARLENESME_RINVOPTPARSTREAMESME_ROPTPARNOTALLWDESME_RINVPARLENESME_RMISSINGOPTPARAMESME_RINVOPTPARAMVALESME_RDELIVERYFAILUREESME_RUNKNOWNERRESME_LAST_ERROR"
+const _CommandStatusType_name = "ESME_ROKESME_RINVMSGLENESME_RINVCMDLENESME_RINVCMDIDESME_RINVBNDSTSESME_RALYBNDESME_RINVPRTFLGESME_RINVREGDLVFLGESME_RSYSERRESME_RINVSRCADRESME_RINVDSTADRESME_RINVMSGIDESME_RBINDFAILESME_RINVPASWDESME_RINVSYSIDESME_RCANCELFAILESME_RREPLACEFAILESME_RMSGQFULESME_RINVSERTYPESME_RADDCUSTFAILESME_RDELCUSTFAILESME_RMODCUSTFAILESME_RENQCUSTFAILESME_RINVCUSTIDESME_RINVCUSTNAMEESME_RINVCUSTADRESME_RINVADRESME_RCUSTEXISTESME_RCUSTNOTEXISTESME_RADDDLFAILESME_RMODDLFAILESME_RDELDLFAILESME_RVIEWDLFAILESME_RLISTDLSFAILESME_RPARAMRETFAILESME_RINVPARAMESME_RINVNUMDESTSESME_RINVDLNAMEESME_RINVDLMEMBDESCESME_RINVDLMEMBTYPESME_RINVDLMODOPTESME_RINVDESTFLAGESME_RINVSUBREPESME_RINVESMCLASSESME_RCNTSUBDLESME_RSUBMITFAILESME_RINVSRCTONESME_RINVSRCNPIESME_RINVDSTTONESME_RINVDSTNPIESME_RINVSYSTYPESME_RINVREPFLAGESME_RINVNUMMSGSESME_RTHROTTLEDESME_RPROVNOTALLWDESME_RINVSCHEDESME_RINVEXPIRYESME_RINVDFTMSGIDESME_RX_T_APPNESME_RX_P_APPNESME_RX_R_APPNESME_RQUERYFAILESME_RINVPGCUSTIDESME_RINVPGCUSTIDLENESME_RINVCITYLENESME_RINVSTATELENESME_RINVZIPPREFIXLENESME_RINVZIPPOSTFIXLENESME_RINVMINLENESME_RINVMINESME_RINVPINLENESME_RINVTERMCODELENESME_RINVCHANNELLENESME_RINVCOVREGIONLENESME_RINVCAPCODELENESME_RINVMDTLENESME_RINVPRIORMSGLENESME_RINVPERMSGLENESME_RINVPGALERTLENESME_RINVSMUSERLENESME_RINVRTDBLENESME_RINVREGDELLENESME_RINVMSGDISTLENESME_RINVPRIORMSGESME_RINVMDTESME_RINVPERMSGESME_RINVMSGDISTESME_RINVPGALERTESME_RINVSMUSERESME_RINVRTDBESME_RINVREGDELESME_RINVOPTPARLENESME_RINVOPTPARSTREAMESME_ROPTPARNOTALLWDESME_RINVPARLENESME_RMISSINGOPTPARAMESME_RINVOPTPARAMVALESME_RDELIVERYFAILUREESME_RUNKNOWNERRESME_RSERTYPUNAUTHESME_RPROHIBITEDESME_RSERTYPUNAVAILESME_RSERTYPDENIEDESME_RINVDCSESME_RINVSRCADDRSUBUNITESME_RINVDSTADDRSUBUNITESME_RINVBCASTFREQINTESME_RINVBCASTALIAS_NAMEESME_RINVBCASTAREAFMTESME_RINVNUMBCAST_AREASESME_RINVBCASTCNTTYPEESME_RINVBCASTMSGCLASSESME_RBCASTFAILESME_RBCASTQUERYFAILESME_RBCASTCANCELFAILESME_RINVBCAST_REPESME_RINVBCASTSRVGRPESME_RINVBCASTCHANINDESME_LAST_ERROR"
 
 var _CommandStatusType_map = map[CommandStatusType]string{
 	0:   _CommandStatusType_name[0:8],
@@ -212,7 +231,26 @@ var _CommandStatusType_map = map[CommandStatusType]string{
 	196: _CommandStatusType_name[1541:1561],
 	254: _CommandStatusType_name[1561:1582],
 	255: _CommandStatusType_name[1582:1598],
-	300: _CommandStatusType_name[1598:1613],
+	256: _CommandStatusType_name[1598:1616],
+	257: _CommandStatusType_name[1616:1632],
+	258: _CommandStatusType_name[1632:1651],
+	259: _CommandStatusType_name[1651:1669],
+	260: _CommandStatusType_name[1669:1681],
+	261: _CommandStatusType_name[1681:1704],
+	262: _CommandStatusType_name[1704:1727],
+	263: _CommandStatusType_name[1727:1748],
+	264: _CommandStatusType_name[1748:1772],
+	265: _CommandStatusType_name[1772:1793],
+	266: _CommandStatusType_name[1793:1816],
+	267: _CommandStatusType_name[1816:1837],
+	268: _CommandStatusType_name[1837:1859],
+	269: _CommandStatusType_name[1859:1874],
+	270: _CommandStatusType_name[1874:1894],
+	271: _CommandStatusType_name[1894:1915],
+	272: _CommandStatusType_name[1915:1933],
+	273: _CommandStatusType_name[1933:1953],
+	274: _CommandStatusType_name[1953:1974],
+	300: _CommandStatusType_name[1974:1989],
 }
 
 func (i CommandStatusType) String() string {
@@ -422,12 +460,49 @@ func (i CommandStatusType) Desc() string {
 		return "Delivery Failure (used for data_sm_resp)"
 	case ESME_RUNKNOWNERR:
 		return "Unknown Error"
+	case ESME_RSERTYPUNAUTH:
+		return "ESME Not authorised to use specified service_type"
+	case ESME_RPROHIBITED:
+		return "ESME Prohibited from using specified operation"
+	case ESME_RSERTYPUNAVAIL:
+		return "Specified service_type is unavailable"
+	case ESME_RSERTYPDENIED:
+		return "Specified service_type is denied"
+	case ESME_RINVDCS:
+		return "Invalid Data Coding Scheme"
+	case ESME_RINVSRCADDRSUBUNIT:
+		return "Source Address Sub unit is Invalid"
+	case ESME_RINVDSTADDRSUBUNIT:
+		return "Destination Address Sub unit is Invalid"
+	case ESME_RINVBCASTFREQINT:
+		return "Broadcast Frequency Interval is invalid"
+	case ESME_RINVBCASTALIAS_NAME:
+		return "Broadcast Alias Name is invalid"
+	case ESME_RINVBCASTAREAFMT:
+		return "Broadcast Area Format is invalid"
+	case ESME_RINVNUMBCAST_AREAS:
+		return "Number of Broadcast Areas is invalid"
+	case ESME_RINVBCASTCNTTYPE:
+		return "Broadcast Content Type is invalid"
+	case ESME_RINVBCASTMSGCLASS:
+		return "Broadcast Message Class is invalid"
+	case ESME_RBCASTFAIL:
+		return "broadcast_sm operation failed"
+	case ESME_RBCASTQUERYFAIL:
+		return "query_broadcast_sm operation failed"
+	case ESME_RBCASTCANCELFAIL:
+		return "cancel_broadcast_sm operation failed"
+	case ESME_RINVBCAST_REP:
+		return "Number of Repeated Broadcasts is invalid"
+	case ESME_RINVBCASTSRVGRP:
+		return "Broadcast Service Group is invalid"
+	case ESME_RINVBCASTCHANIND:
+		return "Broadcast Channel Indicator is invalid"
 	case ESME_LAST_ERROR:
 		return "The value of the last error code"
 	}
 	return i.String()
 }
-
 func _() {
 	// An "invalid array index" compiler error signifies that the constant values have changed.
 	// Re-run the stringer command to generate them again.
@@ -459,50 +534,55 @@ func _() {
 	_ = x[ALERT_NOTIFICATION-258]
 	_ = x[DATA_SM-259]
 	_ = x[DATA_SM_RESP - -2147483389]
+	_ = x[BROADCAST_SM-273]
+	_ = x[BROADCAST_SM_RESP - -2147483375]
+	_ = x[QUERY_BROADCAST_SM-274]
+	_ = x[QUERY_BROADCAST_SM_RESP - -2147483374]
+	_ = x[CANCEL_BROADCAST_SM-275]
+	_ = x[CANCEL_BROADCAST_SM_RESP - -2147483373]
 }
 
-const (
-	_CommandIDType_name_0 = "GENERIC_NACKBIND_RECEIVER_RESPBIND_TRANSMITTER_RESPQUERY_SM_RESPSUBMIT_SM_RESPDELIVER_SM_RESPUNBIND_RESPREPLACE_SM_RESPCANCEL_SM_RESPBIND_TRANSCEIVER_RESP"
-	_CommandIDType_name_1 = "ENQUIRE_LINK_RESP"
-	_CommandIDType_name_2 = "SUBMIT_MULTI_RESP"
-	_CommandIDType_name_3 = "DATA_SM_RESP"
-	_CommandIDType_name_4 = "BIND_RECEIVERBIND_TRANSMITTERQUERY_SMSUBMIT_SMDELIVER_SMUNBINDREPLACE_SMCANCEL_SMBIND_TRANSCEIVER"
-	_CommandIDType_name_5 = "OUTBIND"
-	_CommandIDType_name_6 = "ENQUIRE_LINK"
-	_CommandIDType_name_7 = "SUBMIT_MULTI"
-	_CommandIDType_name_8 = "ALERT_NOTIFICATIONDATA_SM"
-)
+const _CommandIDType_name = "GENERIC_NACKBIND_RECEIVER_RESPBIND_TRANSMITTER_RESPQUERY_SM_RESPSUBMIT_SM_RESPDELIVER_SM_RESPUNBIND_RESPREPLACE_SM_RESPCANCEL_SM_RESPBIND_TRANSCEIVER_RESPENQUIRE_LINK_RESPSUBMIT_MULTI_RESPDATA_SM_RESPBROADCAST_SM_RESPQUERY_BROADCAST_SM_RESPCANCEL_BROADCAST_SM_RESPBIND_RECEIVERBIND_TRANSMITTERQUERY_SMSUBMIT_SMDELIVER_SMUNBINDREPLACE_SMCANCEL_SMBIND_TRANSCEIVEROUTBINDENQUIRE_LINKSUBMIT_MULTIALERT_NOTIFICATIONDATA_SMBROADCAST_SMQUERY_BROADCAST_SMCANCEL_BROADCAST_SM"
 
-var (
-	_CommandIDType_index_0 = [...]uint8{0, 12, 30, 51, 64, 78, 93, 104, 119, 133, 154}
-	_CommandIDType_index_4 = [...]uint8{0, 13, 29, 37, 46, 56, 62, 72, 81, 97}
-	_CommandIDType_index_8 = [...]uint8{0, 18, 25}
-)
+var _CommandIDType_map = map[CommandIDType]string{
+	-2147483648: _CommandIDType_name[0:12],
+	-2147483647: _CommandIDType_name[12:30],
+	-2147483646: _CommandIDType_name[30:51],
+	-2147483645: _CommandIDType_name[51:64],
+	-2147483644: _CommandIDType_name[64:78],
+	-2147483643: _CommandIDType_name[78:93],
+	-2147483642: _CommandIDType_name[93:104],
+	-2147483641: _CommandIDType_name[104:119],
+	-2147483640: _CommandIDType_name[119:133],
+	-2147483639: _CommandIDType_name[133:154],
+	-2147483627: _CommandIDType_name[154:171],
+	-2147483615: _CommandIDType_name[171:188],
+	-2147483389: _CommandIDType_name[188:200],
+	-2147483375: _CommandIDType_name[200:217],
+	-2147483374: _CommandIDType_name[217:240],
+	-2147483373: _CommandIDType_name[240:264],
+	1:           _CommandIDType_name[264:277],
+	2:           _CommandIDType_name[277:293],
+	3:           _CommandIDType_name[293:301],
+	4:           _CommandIDType_name[301:310],
+	5:           _CommandIDType_name[310:320],
+	6:           _CommandIDType_name[320:326],
+	7:           _CommandIDType_name[326:336],
+	8:           _CommandIDType_name[336:345],
+	9:           _CommandIDType_name[345:361],
+	11:          _CommandIDType_name[361:368],
+	21:          _CommandIDType_name[368:380],
+	33:          _CommandIDType_name[380:392],
+	258:         _CommandIDType_name[392:410],
+	259:         _CommandIDType_name[410:417],
+	273:         _CommandIDType_name[417:429],
+	274:         _CommandIDType_name[429:447],
+	275:         _CommandIDType_name[447:466],
+}
 
 func (i CommandIDType) String() string {
-	switch {
-	case -2147483648 <= i && i <= -2147483639:
-		i -= -2147483648
-		return _CommandIDType_name_0[_CommandIDType_index_0[i]:_CommandIDType_index_0[i+1]]
-	case i == -2147483627:
-		return _CommandIDType_name_1
-	case i == -2147483615:
-		return _CommandIDType_name_2
-	case i == -2147483389:
-		return _CommandIDType_name_3
-	case 1 <= i && i <= 9:
-		i -= 1
-		return _CommandIDType_name_4[_CommandIDType_index_4[i]:_CommandIDType_index_4[i+1]]
-	case i == 11:
-		return _CommandIDType_name_5
-	case i == 21:
-		return _CommandIDType_name_6
-	case i == 33:
-		return _CommandIDType_name_7
-	case 258 <= i && i <= 259:
-		i -= 258
-		return _CommandIDType_name_8[_CommandIDType_index_8[i]:_CommandIDType_index_8[i+1]]
-	default:
-		return "CommandIDType(" + strconv.FormatInt(int64(i), 10) + ")"
+	if str, ok := _CommandIDType_map[i]; ok {
+		return str
 	}
+	return "CommandIDType(" + strconv.FormatInt(int64(i), 10) + ")"
 }
@@ -27,6 +27,9 @@ const (
 	// GSM specific, short message must be no larger than 140 octets
 	SM_GSM_MSG_LEN = 140
 
+	// message_payload TLV value is limited by its 2 octets length
+	SM_MSG_PAYLOAD_LEN = 65535
+
 	CONNECTION_CLOSED = 0
 	CONNECTION_OPENED = 1
 
@@ -37,8 +40,12 @@ const (
 	SM_RESPONSE_PNACK = 2
 
 	// Interface_Version
-	SMPP_V33 int8 = int8(-0x33)
-	SMPP_V34      = byte(0x34)
+	//
+	// Deprecated: SMPP_V33 is not interface_version of SMPP 3.3 on the wire, use SMPP_V33_WIRE.
+	SMPP_V33      int8 = int8(-0x33)
+	SMPP_V33_WIRE      = byte(0x33)
+	SMPP_V34           = byte(0x34)
+	SMPP_V50           = byte(0x50)
 
 	// Address_TON
 	GSM_TON_UNKNOWN       = byte(0x00)
@@ -100,6 +107,7 @@ const (
 	SM_STORE_FORWARD_MODE = 0x03 // Use store & forward
 
 	// Send/Receive TDMA & CDMA Message Type
+	SM_MSG_TYPE_MASK          = 0x3C // Message Type bits (5-2)
 	SM_SMSC_DLV_RCPT_TYPE     = 0x04 // Recv Msg contains SMSC delivery receipt
 	SM_ESME_DLV_ACK_TYPE      = 0x08 // Send/Recv Msg contains ESME delivery acknowledgement
 	SM_ESME_MAN_USER_ACK_TYPE = 0x10 // Send/Recv Msg contains manual/user acknowledgment
@@ -220,6 +228,116 @@ const (
 	// USSD Service Op
 	OPT_PAR_USSD_SER_OP = 0x0501
 
+	// SMPP 5.0 optional parameters
+
+	// Congestion State, 0 (idle) to 100 (congested)
+	OPT_PAR_CONGESTION_STATE     = 0x0428
+	OPT_PAR_CONGESTION_STATE_MAX = 100
+
+	// Broadcast Channel Indicator
+	OPT_PAR_BCAST_CHANNEL_IND = 0x0600
+
+	// Broadcast Content Type
+	OPT_PAR_BCAST_CONTENT_TYPE = 0x0601
+
+	// Broadcast Content Type Info
+	OPT_PAR_BCAST_CONTENT_TYPE_INFO     = 0x0602
+	OPT_PAR_BCAST_CONTENT_TYPE_INFO_MAX = 255
+
+	// Broadcast Message Class
+	OPT_PAR_BCAST_MSG_CLASS = 0x0603
+
+	// Broadcast Repetition Number
+	OPT_PAR_BCAST_REP_NUM = 0x0604
+
+	// Broadcast Frequency Interval
+	OPT_PAR_BCAST_FREQ_INTERVAL = 0x0605
+
+	// Broadcast Area Identifier
+	OPT_PAR_BCAST_AREA_ID     = 0x0606
+	OPT_PAR_BCAST_AREA_ID_MIN = 1
+	OPT_PAR_BCAST_AREA_ID_MAX = 100
+
+	// Broadcast Error Status
+	OPT_PAR_BCAST_ERR_STATUS = 0x0607
+
+	// Broadcast Area Success
+	OPT_PAR_BCAST_AREA_SUCCESS = 0x0608
+
+	// Broadcast End Time
+	OPT_PAR_BCAST_END_TIME     = 0x0609
+	OPT_PAR_BCAST_END_TIME_MIN = 1
+	OPT_PAR_BCAST_END_TIME_MAX = 17
+
+	// Broadcast Service Group
+	OPT_PAR_BCAST_SRV_GROUP     = 0x060A
+	OPT_PAR_BCAST_SRV_GROUP_MAX = 255
+
+	// Billing Identification
+	OPT_PAR_BILLING_ID     = 0x060B
+	OPT_PAR_BILLING_ID_MAX = 1024
+
+	// Source Network Id
+	OPT_PAR_SRC_NW_ID     = 0x060D
+	OPT_PAR_SRC_NW_ID_MIN = 1
+	OPT_PAR_SRC_NW_ID_MAX = 65
+
+	// Dest Network Id
+	OPT_PAR_DST_NW_ID     = 0x060E
+	OPT_PAR_DST_NW_ID_MIN = 1
+	OPT_PAR_DST_NW_ID_MAX = 65
+
+	// Source Node Id
+	OPT_PAR_SRC_NODE_ID = 0x060F
+
+	// Dest Node Id
+	OPT_PAR_DST_NODE_ID = 0x0610
+
+	// Dest Address NP Resolution
+	OPT_PAR_DST_ADDR_NP_RESOLUTION = 0x0611
+
+	// Dest Address NP Information
+	OPT_PAR_DST_ADDR_NP_INFO = 0x0612
+
+	// Dest Address NP Country
+	OPT_PAR_DST_ADDR_NP_COUNTRY     = 0x0613
+	OPT_PAR_DST_ADDR_NP_COUNTRY_MIN = 1
+	OPT_PAR_DST_ADDR_NP_COUNTRY_MAX = 5
+
+	// ussd_service_op values, extended by SMPP 5.0 with responses and confirmations
+	USSD_PSSD_INDICATION = byte(0x00)
+	USSD_PSSR_INDICATION = byte(0x01)
+	USSD_USSR_REQUEST    = byte(0x02)
+	USSD_USSN_REQUEST    = byte(0x03)
+	USSD_PSSD_RESPONSE   = byte(0x10)
+	USSD_PSSR_RESPONSE   = byte(0x11)
+	USSD_USSR_CONFIRM    = byte(0x12)
+	USSD_USSN_CONFIRM    = byte(0x13)
+
+	// Broadcast area success, percentage of the area reached, or unknown
+	BCAST_AREA_SUCCESS_UNKNOWN = byte(0xFF)
+
+	// Broadcast area format, first octet of broadcast_area_identifier
+	BCAST_AREA_FORMAT_ALIAS         = byte(0x00)
+	BCAST_AREA_FORMAT_ELLIPSOID_ARC = byte(0x01)
+	BCAST_AREA_FORMAT_POLYGON       = byte(0x02)
+
+	// Broadcast content type network, first octet of broadcast_content_type
+	BCAST_NETWORK_GENERIC = byte(0x00)
+	BCAST_NETWORK_GSM     = byte(0x01)
+	BCAST_NETWORK_TDMA    = byte(0x02)
+	BCAST_NETWORK_CDMA    = byte(0x03)
+
+	// Broadcast frequency interval unit, first octet of broadcast_frequency_interval
+	BCAST_FREQ_ASAP    = byte(0x00)
+	BCAST_FREQ_SECONDS = byte(0x08)
+	BCAST_FREQ_MINUTES = byte(0x09)
+	BCAST_FREQ_HOURS   = byte(0x0A)
+	BCAST_FREQ_DAYS    = byte(0x0B)
+	BCAST_FREQ_WEEKS   = byte(0x0C)
+	BCAST_FREQ_MONTHS  = byte(0x0D)
+	BCAST_FREQ_YEARS   = byte(0x0E)
+
 	// Priority
 	SM_NOPRIORITY = 0
 	SM_PRIORITY   = 1
@@ -30,6 +30,18 @@ var (
 	// ErrShortMessageLengthTooLarge indicates short message length is too large.
 	ErrShortMessageLengthTooLarge error = &SmppErr{err: fmt.Sprintf("Encoded short message data exceeds size of %d", data.SM_MSG_LEN), serialVersionUID: 78237205927624}
 
+	// ErrMessagePayloadTooLarge indicates message payload length is too large.
+	ErrMessagePayloadTooLarge = fmt.Errorf("Encoded message payload exceeds size of %d", data.SM_MSG_PAYLOAD_LEN)
+
+	// ErrInvalidTLV indicates optional parameter value violates its type or length limits
+	ErrInvalidTLV = fmt.Errorf("Optional parameter value is malformed")
+
 	// ErrUDHTooLong UDH-L is larger than total length of short message data
 	ErrUDHTooLong = fmt.Errorf("User Data Header is too long for PDU short message")
+
+	// ErrNotDeliveryReceipt indicates PDU does not carry a delivery receipt
+	ErrNotDeliveryReceipt = fmt.Errorf("PDU is not a delivery receipt")
+
+	// ErrInvalidDeliveryReceipt indicates delivery receipt has no receipted message id
+	ErrInvalidDeliveryReceipt = fmt.Errorf("Delivery receipt has no message id")
 )
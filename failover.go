@@ -0,0 +1,196 @@
+package gosmpp
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+var (
+	// ErrNoEndpoint indicates FailoverConnector has no endpoint to connect to.
+	ErrNoEndpoint = errors.New("no SMSC endpoint configured")
+)
+
+// Endpoint is an SMSC address of FailoverConnector.
+type Endpoint struct {
+	// Addr is SMSC address.
+	Addr string
+
+	// Priority of the endpoint, lower is preferred.
+	// Endpoints with the lowest priority are primary, the others are secondary.
+	Priority int
+
+	// Weight distributes binds among endpoints of the same priority.
+	// Endpoints of zero weight are tried after weighted ones, in the given order.
+	Weight int
+}
+
+// FailoverConnector connects to one of multiple SMSC endpoints.
+//
+// Endpoints are tried by priority, then by weight, until one of them binds.
+// Once bound, the endpoint is kept and tried first on the following rebinds.
+// While bound to a secondary endpoint, Session periodically tries to bind to
+// a more preferred endpoint and switches to it on success.
+type FailoverConnector struct {
+	connector
+	endpoints []Endpoint
+	interval  time.Duration
+
+	mu      sync.Mutex
+	current *Endpoint
+}
+
+// NewFailoverConnector returns FailoverConnector for given binding type. Auth.SMSC is not used.
+//
+// `failbackInterval` indicates how often Session tries to return from a secondary endpoint to a more preferred one.
+// Setting `failbackInterval <= 0` disables returning, the bound endpoint is kept until it fails.
+func NewFailoverConnector(dialer Dialer, auth Auth, bindingType pdu.BindingType, endpoints []Endpoint, failbackInterval time.Duration, opts ...connectorOption) *FailoverConnector {
+	c := &FailoverConnector{
+		connector: connector{
+			dialer:      dialer,
+			auth:        auth,
+			bindingType: bindingType,
+		},
+		endpoints: append([]Endpoint(nil), endpoints...),
+		interval:  failbackInterval,
+	}
+	for _, opt := range opts {
+		opt(&c.connector)
+	}
+	return c
+}
+
+// Connect binds to the current endpoint, or to the others in order of preference if it fails.
+//
+// Errors of all tried endpoints are joined.
+func (c *FailoverConnector) Connect() (conn *Connection, err error) {
+	c.mu.Lock()
+	current := c.current
+	c.mu.Unlock()
+
+	candidates := c.ordered(func(Endpoint) bool { return true })
+	if current != nil {
+		candidates = append([]Endpoint{*current}, without(candidates, *current)...)
+	}
+	return c.connectAny(candidates)
+}
+
+// Current returns the endpoint bound by the last successful Connect.
+func (c *FailoverConnector) Current() (endpoint Endpoint, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.current != nil {
+		endpoint, ok = *c.current, true
+	}
+	return
+}
+
+// failbackInterval implements failbackConnector.
+func (c *FailoverConnector) failbackInterval() time.Duration {
+	return c.interval
+}
+
+// failback binds to an endpoint preferred over the current one.
+// Nil connection is returned if current endpoint is already primary.
+func (c *FailoverConnector) failback() (conn *Connection, err error) {
+	c.mu.Lock()
+	current := c.current
+	c.mu.Unlock()
+
+	if current == nil {
+		return
+	}
+
+	candidates := c.ordered(func(e Endpoint) bool { return e.Priority < current.Priority })
+	if len(candidates) == 0 {
+		return
+	}
+	return c.connectAny(candidates)
+}
+
+func (c *FailoverConnector) connectAny(candidates []Endpoint) (conn *Connection, err error) {
+	if len(candidates) == 0 {
+		return nil, ErrNoEndpoint
+	}
+
+	errs := make([]error, 0, len(candidates))
+	for _, endpoint := range candidates {
+		if conn, err = c.connectTo(endpoint); err == nil {
+			c.mu.Lock()
+			c.current = &endpoint
+			c.mu.Unlock()
+			return
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (c *FailoverConnector) connectTo(endpoint Endpoint) (conn *Connection, err error) {
+	return c.bindTo(endpoint.Addr)
+}
+
+// ordered returns endpoints matching filter, by priority and shuffled by weight within the same priority.
+func (c *FailoverConnector) ordered(filter func(Endpoint) bool) (endpoints []Endpoint) {
+	for _, endpoint := range c.endpoints {
+		if filter(endpoint) {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		return endpoints[i].Priority < endpoints[j].Priority
+	})
+
+	for start := 0; start < len(endpoints); {
+		end := start + 1
+		for end < len(endpoints) && endpoints[end].Priority == endpoints[start].Priority {
+			end++
+		}
+		shuffleByWeight(endpoints[start:end])
+		start = end
+	}
+	return
+}
+
+// shuffleByWeight orders endpoints by weighted random selection, zero weights last keeping their order.
+func shuffleByWeight(endpoints []Endpoint) {
+	for i := range endpoints {
+		total := 0
+		for _, endpoint := range endpoints[i:] {
+			if endpoint.Weight > 0 {
+				total += endpoint.Weight
+			}
+		}
+		if total == 0 {
+			return
+		}
+
+		pick := rand.Intn(total)
+		for j := i; j < len(endpoints); j++ {
+			if endpoints[j].Weight <= 0 {
+				continue
+			}
+			if pick -= endpoints[j].Weight; pick < 0 {
+				// keep relative order of the others
+				picked := endpoints[j]
+				copy(endpoints[i+1:j+1], endpoints[i:j])
+				endpoints[i] = picked
+				break
+			}
+		}
+	}
+}
+
+func without(endpoints []Endpoint, endpoint Endpoint) (filtered []Endpoint) {
+	for _, e := range endpoints {
+		if e != endpoint {
+			filtered = append(filtered, e)
+		}
+	}
+	return
+}
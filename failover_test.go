@@ -0,0 +1,138 @@
+package gosmpp
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+// reserveAddr returns a local address nobody listens on.
+func reserveAddr(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	_ = l.Close()
+	return l.Addr().String()
+}
+
+func newTestServer() *Server {
+	return NewServer("GoSMSC", nil, func(*ServerSession) Settings {
+		return Settings{ReadTimeout: 2 * time.Second}
+	})
+}
+
+func TestFailoverConnector(t *testing.T) {
+	primary, secondary := reserveAddr(t), startServer(t, newTestServer())
+
+	auth := Auth{SystemID: "esme", Password: "secret"}
+	c := NewFailoverConnector(NonTLSDialer, auth, pdu.Transceiver, []Endpoint{
+		{Addr: secondary, Priority: 1},
+		{Addr: primary},
+	}, 50*time.Millisecond)
+
+	var rebinds int32
+	rebound := make(chan string, 4)
+	session, err := NewSession(c, Settings{
+		ReadTimeout: 2 * time.Second,
+		OnRebind: func() {
+			atomic.AddInt32(&rebinds, 1)
+		},
+		OnRebindEndpoint: func(endpoint string) {
+			rebound <- endpoint
+		},
+	}, 50*time.Millisecond)
+	require.NoError(t, err)
+	defer func() {
+		_ = session.Close()
+	}()
+
+	current, ok := c.Current()
+	require.True(t, ok)
+	require.Equal(t, secondary, current.Addr)
+
+	// primary is back, session returns to it
+	l, err := net.Listen("tcp", primary)
+	require.NoError(t, err)
+	primaryServer := newTestServer()
+	go func() {
+		_ = primaryServer.Serve(l)
+	}()
+
+	require.Equal(t, primary, <-rebound)
+	current, _ = c.Current()
+	require.Equal(t, primary, current.Addr)
+	require.Eventually(t, func() bool {
+		return len(primaryServer.Sessions()) == 1
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, session.Transceiver().Submit(pdu.NewSubmitSM()))
+
+	// primary fails, rebinding fails over to secondary
+	require.NoError(t, primaryServer.Close())
+	require.Equal(t, secondary, <-rebound)
+	require.EqualValues(t, 2, atomic.LoadInt32(&rebinds))
+
+	t.Run("Sticky", func(t *testing.T) {
+		c := NewFailoverConnector(NonTLSDialer, auth, pdu.Transceiver, []Endpoint{
+			{Addr: reserveAddr(t)},
+			{Addr: secondary},
+			{Addr: startServer(t, newTestServer())},
+		}, 0)
+
+		for i := 0; i < 2; i++ {
+			conn, err := c.Connect()
+			require.NoError(t, err)
+			require.Equal(t, secondary, conn.Endpoint())
+			_ = conn.Close()
+		}
+
+		// already on primary
+		conn, err := c.failback()
+		require.NoError(t, err)
+		require.Nil(t, conn)
+	})
+
+	t.Run("AllFailed", func(t *testing.T) {
+		c := NewFailoverConnector(NonTLSDialer, auth, pdu.Transceiver, []Endpoint{
+			{Addr: reserveAddr(t)},
+			{Addr: reserveAddr(t)},
+		}, 0)
+
+		_, err := c.Connect()
+		var dialErr DialError
+		require.ErrorAs(t, err, &dialErr)
+		_, ok := c.Current()
+		require.False(t, ok)
+
+		_, err = NewFailoverConnector(NonTLSDialer, auth, pdu.Transceiver, nil, 0).Connect()
+		require.Equal(t, ErrNoEndpoint, err)
+	})
+}
+
+func TestFailoverOrder(t *testing.T) {
+	c := NewFailoverConnector(NonTLSDialer, Auth{}, pdu.Transceiver, []Endpoint{
+		{Addr: "d", Priority: 2},
+		{Addr: "b", Priority: 1},
+		{Addr: "c", Priority: 1},
+		{Addr: "a"},
+		{Addr: "w0", Priority: 3},
+		{Addr: "w1", Priority: 3, Weight: 1},
+		{Addr: "w2", Priority: 3, Weight: 3},
+	}, 0)
+
+	picked := map[string]int{}
+	for i := 0; i < 400; i++ {
+		var addrs []string
+		for _, e := range c.ordered(func(Endpoint) bool { return true }) {
+			addrs = append(addrs, e.Addr)
+		}
+		require.Equal(t, []string{"a", "b", "c", "d"}, addrs[:4])
+		require.ElementsMatch(t, []string{"w1", "w2"}, addrs[4:6])
+		require.Equal(t, "w0", addrs[6])
+		picked[addrs[4]]++
+	}
+	require.Greater(t, picked["w2"], picked["w1"])
+}
@@ -0,0 +1,140 @@
+package gosmpp
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+const defaultInboundWorkers = 4
+
+// InboundWorkers settings for handling received requests on a pool of workers,
+// instead of the goroutine reading from SMSC.
+//
+// Received requests, like deliver_sm, are passed to OnPDU, OnAllPDU or OnReceivedPduRequest by workers,
+// so slow callback does not delay reading. Segments of multipart messages are reassembled by workers too,
+// ConcatReassembly.OnMessage is called by the worker receiving the last segment. Enquire_link, unbind and responses are still handled
+// on the reading goroutine. Responses returned by callbacks are sent as usual.
+type InboundWorkers struct {
+	// Workers is the number of goroutines handling received requests.
+	//
+	// Default: 4.
+	Workers int
+
+	// MaxInFlight is the maximum number of received requests queued or being handled.
+	// Reading from SMSC pauses until a request is handled once reached.
+	//
+	// Default: number of workers.
+	MaxInFlight int
+
+	// Key keeps order of requests with the same key, e.g. KeyBySourceAddr.
+	// Requests with the same key are handled one by one by the same worker,
+	// requests with empty key are spread across workers.
+	//
+	// Nil value handles requests in any order.
+	Key InboundKeyFunc
+}
+
+// KeyBySourceAddr keys DeliverSM, DataSM and SubmitSM by source address,
+// keeping messages from the same subscriber in order.
+func KeyBySourceAddr(p pdu.PDU) string {
+	switch pp := p.(type) {
+	case *pdu.DeliverSM:
+		return pp.SourceAddr.Address()
+	case *pdu.DataSM:
+		return pp.SourceAddr.Address()
+	case *pdu.SubmitSM:
+		return pp.SourceAddr.Address()
+	}
+	return ""
+}
+
+type inboundPool struct {
+	key      InboundKeyFunc
+	handle   func(pdu.PDU)
+	queues   []chan pdu.PDU
+	workers  int
+	inFlight chan struct{}
+	next     uint32
+	wg       sync.WaitGroup
+}
+
+func newInboundPool(settings InboundWorkers, handle func(pdu.PDU)) *inboundPool {
+	if settings.Workers <= 0 {
+		settings.Workers = defaultInboundWorkers
+	}
+	if settings.MaxInFlight <= 0 {
+		settings.MaxInFlight = settings.Workers
+	}
+
+	p := &inboundPool{
+		key:      settings.Key,
+		handle:   handle,
+		workers:  settings.Workers,
+		inFlight: make(chan struct{}, settings.MaxInFlight),
+	}
+
+	// without key, workers share a single queue
+	queues := 1
+	if p.key != nil {
+		queues = settings.Workers
+	}
+	p.queues = make([]chan pdu.PDU, queues)
+	for i := range p.queues {
+		// never blocks, as in-flight requests are limited before queueing
+		p.queues[i] = make(chan pdu.PDU, settings.MaxInFlight)
+	}
+	return p
+}
+
+func (p *inboundPool) start() {
+	p.wg.Add(p.workers)
+	for i := 0; i < p.workers; i++ {
+		go func(queue chan pdu.PDU) {
+			defer p.wg.Done()
+			for req := range queue {
+				p.handle(req)
+				<-p.inFlight
+			}
+		}(p.queues[i%len(p.queues)])
+	}
+}
+
+// dispatch queues the request, waiting for in-flight requests to be handled if limit is reached.
+// Returns false if ctx is done before.
+func (p *inboundPool) dispatch(ctx context.Context, req pdu.PDU) bool {
+	select {
+	case p.inFlight <- struct{}{}:
+	case <-ctx.Done():
+		return false
+	}
+
+	p.queues[p.queueOf(req)] <- req
+	return true
+}
+
+func (p *inboundPool) queueOf(req pdu.PDU) int {
+	if len(p.queues) == 1 {
+		return 0
+	}
+
+	key := p.key(req)
+	if key == "" {
+		return int(atomic.AddUint32(&p.next, 1) % uint32(len(p.queues)))
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(len(p.queues)))
+}
+
+// stop waits for queued requests to be handled.
+func (p *inboundPool) stop() {
+	for _, queue := range p.queues {
+		close(queue)
+	}
+	p.wg.Wait()
+}
@@ -0,0 +1,145 @@
+package gosmpp
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInboundWorkers(t *testing.T) {
+	var (
+		sessions  = make(chan *ServerSession, 1)
+		responses int32
+	)
+	addr := startServer(t, NewServer("GoSMSC", nil, func(sess *ServerSession) Settings {
+		return Settings{
+			ReadTimeout: 2 * time.Second,
+			OnAllPDU: func(p pdu.PDU) (pdu.PDU, bool) {
+				switch p.(type) {
+				case *pdu.DeliverSMResp:
+					atomic.AddInt32(&responses, 1)
+					return nil, false
+				case *pdu.SubmitSM:
+					// session is started by now
+					sessions <- sess
+				}
+				return p.GetResponse(), false
+			},
+		}
+	}))
+
+	var (
+		mu       sync.Mutex
+		handled  = make(map[string][]int32)
+		release  = make(chan struct{})
+		inFlight int32
+		maxSeen  int32
+	)
+	session, err := NewSession(TRXConnector(NonTLSDialer, Auth{SMSC: addr, SystemID: "esme"}), Settings{
+		ReadTimeout: 2 * time.Second,
+		InboundWorkers: &InboundWorkers{
+			Workers:     4,
+			MaxInFlight: 8,
+			Key:         KeyBySourceAddr,
+		},
+		OnPDU: func(p pdu.PDU, responded bool) {
+			sm, ok := p.(*pdu.DeliverSM)
+			if !ok {
+				return
+			}
+			if !responded {
+				t.Error("deliver_sm not responded")
+			}
+
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				m := atomic.LoadInt32(&maxSeen)
+				if n <= m || atomic.CompareAndSwapInt32(&maxSeen, m, n) {
+					break
+				}
+			}
+
+			source := sm.SourceAddr.Address()
+			if source == "1000" {
+				<-release
+			}
+
+			mu.Lock()
+			handled[source] = append(handled[source], sm.SequenceNumber)
+			mu.Unlock()
+		},
+	}, -1)
+	require.NoError(t, err)
+	defer func() {
+		_ = session.Close()
+	}()
+	require.NoError(t, session.Transmitter().Submit(pdu.NewSubmitSM()))
+	sess := <-sessions
+
+	// 1000 hashes to a worker other than the ones of 2000 and 3000
+	sent := make(map[string][]int32)
+	for _, source := range []string{"1000", "2000", "1000", "3000", "2000", "3000", "2000"} {
+		sm := pdu.NewDeliverSM().(*pdu.DeliverSM)
+		require.NoError(t, sm.SourceAddr.SetAddress(source))
+		sent[source] = append(sent[source], sm.SequenceNumber)
+		require.NoError(t, sess.Submit(sm))
+	}
+
+	count := func() (n int) {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, seqs := range handled {
+			n += len(seqs)
+		}
+		return
+	}
+
+	// slow subscriber does not hold back others, nor reading
+	require.Eventually(t, func() bool {
+		return count() == 5
+	}, time.Second, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = session.SubmitWithContext(ctx, pdu.NewEnquireLink())
+	require.NoError(t, err)
+
+	close(release)
+	require.Eventually(t, func() bool {
+		return count() == 7 && atomic.LoadInt32(&responses) == 7
+	}, time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	require.Equal(t, sent, handled)
+	mu.Unlock()
+	require.LessOrEqual(t, atomic.LoadInt32(&maxSeen), int32(4))
+}
+
+func TestInboundPoolMaxInFlight(t *testing.T) {
+	release := make(chan struct{})
+	var handled int32
+	pool := newInboundPool(InboundWorkers{Workers: 2, MaxInFlight: 3}, func(pdu.PDU) {
+		<-release
+		atomic.AddInt32(&handled, 1)
+	})
+	pool.start()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	for i := 0; i < 3; i++ {
+		require.True(t, pool.dispatch(ctx, pdu.NewDeliverSM()))
+	}
+	// limit reached
+	require.False(t, pool.dispatch(ctx, pdu.NewDeliverSM()))
+
+	close(release)
+	pool.stop()
+	require.EqualValues(t, 3, atomic.LoadInt32(&handled))
+}
@@ -0,0 +1,160 @@
+package gosmpp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// Metrics collects session metrics. Implementation must be safe for concurrent use.
+//
+// Package github.com/linxGnu/gosmpp/metrics/prometheus provides Prometheus collectors.
+type Metrics interface {
+	// PDUSent counts PDU written to SMSC.
+	PDUSent(commandID data.CommandIDType)
+
+	// PDUReceived counts PDU read from SMSC.
+	PDUReceived(commandID data.CommandIDType)
+
+	// ResponseStatus counts received response by its command id and status.
+	ResponseStatus(commandID data.CommandIDType, status data.CommandStatusType)
+
+	// ResponseLatency observes time from sending request to receiving its response.
+	// Only observed with WindowedRequestTracking, where requests are kept along with their sent time.
+	ResponseLatency(commandID data.CommandIDType, latency time.Duration)
+
+	// WindowSize reports number of requests waiting for response in the window.
+	WindowSize(size int)
+
+	// RequestExpired counts request expired in the window without response.
+	RequestExpired(commandID data.CommandIDType)
+
+	// RebindAttempt counts rebind attempt, with error if failed.
+	RebindAttempt(err error)
+
+	// EnquireLinkRTT observes round trip time of enquire_link.
+	EnquireLinkRTT(rtt time.Duration)
+}
+
+// NopMetrics is a Metrics doing nothing. It could be embedded to implement only needed methods.
+type NopMetrics struct{}
+
+// PDUSent implements Metrics.
+func (NopMetrics) PDUSent(data.CommandIDType) {}
+
+// PDUReceived implements Metrics.
+func (NopMetrics) PDUReceived(data.CommandIDType) {}
+
+// ResponseStatus implements Metrics.
+func (NopMetrics) ResponseStatus(data.CommandIDType, data.CommandStatusType) {}
+
+// ResponseLatency implements Metrics.
+func (NopMetrics) ResponseLatency(data.CommandIDType, time.Duration) {}
+
+// WindowSize implements Metrics.
+func (NopMetrics) WindowSize(int) {}
+
+// RequestExpired implements Metrics.
+func (NopMetrics) RequestExpired(data.CommandIDType) {}
+
+// RebindAttempt implements Metrics.
+func (NopMetrics) RebindAttempt(error) {}
+
+// EnquireLinkRTT implements Metrics.
+func (NopMetrics) EnquireLinkRTT(time.Duration) {}
+
+// metricsRecorder feeds Metrics from transmitter and receiver of a bind. Nil recorder records nothing.
+type metricsRecorder struct {
+	metrics Metrics
+
+	// the last enquire_link sent, for measuring RTT
+	mu              sync.Mutex
+	enquireLinkSeq  int32
+	enquireLinkSent time.Time
+}
+
+func newMetricsRecorder(metrics Metrics) *metricsRecorder {
+	if metrics == nil {
+		return nil
+	}
+	return &metricsRecorder{metrics: metrics}
+}
+
+// sending is called right before writing PDU, so that a fast response never comes first.
+func (r *metricsRecorder) sending(p pdu.PDU) {
+	if r == nil {
+		return
+	}
+
+	if _, ok := p.(*pdu.EnquireLink); ok {
+		r.mu.Lock()
+		r.enquireLinkSeq, r.enquireLinkSent = p.GetSequenceNumber(), time.Now()
+		r.mu.Unlock()
+	}
+}
+
+func (r *metricsRecorder) sent(p pdu.PDU) {
+	if r != nil {
+		r.metrics.PDUSent(p.GetHeader().CommandID)
+	}
+}
+
+// received records PDU read from SMSC. Latency of response is computed from its request in store, if any.
+func (r *metricsRecorder) received(p pdu.PDU, store RequestStore, storeAccessTimeout time.Duration) {
+	if r == nil {
+		return
+	}
+
+	header := p.GetHeader()
+	r.metrics.PDUReceived(header.CommandID)
+
+	if !isResponse(p) {
+		return
+	}
+	r.metrics.ResponseStatus(header.CommandID, header.CommandStatus)
+
+	if _, ok := p.(*pdu.EnquireLinkResp); ok {
+		r.mu.Lock()
+		if r.enquireLinkSeq == header.SequenceNumber && !r.enquireLinkSent.IsZero() {
+			r.metrics.EnquireLinkRTT(time.Since(r.enquireLinkSent))
+			r.enquireLinkSent = time.Time{}
+		}
+		r.mu.Unlock()
+	}
+
+	if store != nil {
+		ctx, cancelFunc := context.WithTimeout(context.Background(), storeAccessTimeout)
+		defer cancelFunc()
+		if request, ok := store.Get(ctx, header.SequenceNumber); ok {
+			r.metrics.ResponseLatency(header.CommandID, time.Since(request.TimeSent))
+		}
+	}
+}
+
+// window reports number of requests in store.
+func (r *metricsRecorder) window(store RequestStore, storeAccessTimeout time.Duration) {
+	if r == nil || store == nil {
+		return
+	}
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), storeAccessTimeout)
+	defer cancelFunc()
+	if size, err := store.Length(ctx); err == nil {
+		r.metrics.WindowSize(size)
+	}
+}
+
+func (r *metricsRecorder) windowSize(size int) {
+	if r != nil {
+		r.metrics.WindowSize(size)
+	}
+}
+
+func (r *metricsRecorder) expired(p pdu.PDU) {
+	if r != nil {
+		r.metrics.RequestExpired(p.GetHeader().CommandID)
+	}
+}
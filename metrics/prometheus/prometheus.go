@@ -0,0 +1,171 @@
+// Package prometheus exposes gosmpp session metrics as Prometheus collectors.
+//
+// It lives in its own module, so that the core module does not depend on Prometheus client.
+//
+//	m := prometheus.New("smpp", prom.Labels{"bind": "operator-a"})
+//	prom.MustRegister(m)
+//
+//	session, err := gosmpp.NewSession(connector, gosmpp.Settings{
+//		Metrics: m,
+//		...
+//	}, rebindingInterval)
+package prometheus
+
+import (
+	"time"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics implements gosmpp.Metrics and prometheus.Collector.
+//
+// Window size is a gauge, use one Metrics per session, distinguished by const labels.
+type Metrics struct {
+	pduSent         *prom.CounterVec
+	pduReceived     *prom.CounterVec
+	responseStatus  *prom.CounterVec
+	responseLatency *prom.HistogramVec
+	windowSize      prom.Gauge
+	requestExpired  *prom.CounterVec
+	rebindAttempts  *prom.CounterVec
+	enquireLinkRTT  prom.Histogram
+}
+
+var _ gosmpp.Metrics = (*Metrics)(nil)
+
+// New creates collectors with given namespace and const labels.
+func New(namespace string, constLabels prom.Labels) *Metrics {
+	return &Metrics{
+		pduSent: prom.NewCounterVec(prom.CounterOpts{
+			Namespace:   namespace,
+			Name:        "pdu_sent_total",
+			Help:        "Number of PDUs written to SMSC, by command.",
+			ConstLabels: constLabels,
+		}, []string{"command"}),
+
+		pduReceived: prom.NewCounterVec(prom.CounterOpts{
+			Namespace:   namespace,
+			Name:        "pdu_received_total",
+			Help:        "Number of PDUs read from SMSC, by command.",
+			ConstLabels: constLabels,
+		}, []string{"command"}),
+
+		responseStatus: prom.NewCounterVec(prom.CounterOpts{
+			Namespace:   namespace,
+			Name:        "response_status_total",
+			Help:        "Number of responses received from SMSC, by command and status.",
+			ConstLabels: constLabels,
+		}, []string{"command", "status"}),
+
+		responseLatency: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "response_latency_seconds",
+			Help:        "Time from sending request to receiving its response, by response command.",
+			ConstLabels: constLabels,
+			Buckets:     prom.ExponentialBuckets(0.005, 2, 12),
+		}, []string{"command"}),
+
+		windowSize: prom.NewGauge(prom.GaugeOpts{
+			Namespace:   namespace,
+			Name:        "window_size",
+			Help:        "Number of requests waiting for response.",
+			ConstLabels: constLabels,
+		}),
+
+		requestExpired: prom.NewCounterVec(prom.CounterOpts{
+			Namespace:   namespace,
+			Name:        "request_expired_total",
+			Help:        "Number of requests expired without response, by command.",
+			ConstLabels: constLabels,
+		}, []string{"command"}),
+
+		rebindAttempts: prom.NewCounterVec(prom.CounterOpts{
+			Namespace:   namespace,
+			Name:        "rebind_attempts_total",
+			Help:        "Number of rebind attempts, by result.",
+			ConstLabels: constLabels,
+		}, []string{"result"}),
+
+		enquireLinkRTT: prom.NewHistogram(prom.HistogramOpts{
+			Namespace:   namespace,
+			Name:        "enquire_link_rtt_seconds",
+			Help:        "Round trip time of enquire_link.",
+			ConstLabels: constLabels,
+			Buckets:     prom.ExponentialBuckets(0.005, 2, 12),
+		}),
+	}
+}
+
+func (m *Metrics) collectors() []prom.Collector {
+	return []prom.Collector{
+		m.pduSent,
+		m.pduReceived,
+		m.responseStatus,
+		m.responseLatency,
+		m.windowSize,
+		m.requestExpired,
+		m.rebindAttempts,
+		m.enquireLinkRTT,
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prom.Desc) {
+	for _, c := range m.collectors() {
+		c.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prom.Metric) {
+	for _, c := range m.collectors() {
+		c.Collect(ch)
+	}
+}
+
+// PDUSent implements gosmpp.Metrics.
+func (m *Metrics) PDUSent(commandID data.CommandIDType) {
+	m.pduSent.WithLabelValues(commandID.String()).Inc()
+}
+
+// PDUReceived implements gosmpp.Metrics.
+func (m *Metrics) PDUReceived(commandID data.CommandIDType) {
+	m.pduReceived.WithLabelValues(commandID.String()).Inc()
+}
+
+// ResponseStatus implements gosmpp.Metrics.
+func (m *Metrics) ResponseStatus(commandID data.CommandIDType, status data.CommandStatusType) {
+	m.responseStatus.WithLabelValues(commandID.String(), status.String()).Inc()
+}
+
+// ResponseLatency implements gosmpp.Metrics.
+func (m *Metrics) ResponseLatency(commandID data.CommandIDType, latency time.Duration) {
+	m.responseLatency.WithLabelValues(commandID.String()).Observe(latency.Seconds())
+}
+
+// WindowSize implements gosmpp.Metrics.
+func (m *Metrics) WindowSize(size int) {
+	m.windowSize.Set(float64(size))
+}
+
+// RequestExpired implements gosmpp.Metrics.
+func (m *Metrics) RequestExpired(commandID data.CommandIDType) {
+	m.requestExpired.WithLabelValues(commandID.String()).Inc()
+}
+
+// RebindAttempt implements gosmpp.Metrics.
+func (m *Metrics) RebindAttempt(err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	m.rebindAttempts.WithLabelValues(result).Inc()
+}
+
+// EnquireLinkRTT implements gosmpp.Metrics.
+func (m *Metrics) EnquireLinkRTT(rtt time.Duration) {
+	m.enquireLinkRTT.Observe(rtt.Seconds())
+}
@@ -0,0 +1,51 @@
+package prometheus
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	m := New("smpp", prom.Labels{"bind": "test"})
+
+	registry := prom.NewRegistry()
+	require.NoError(t, registry.Register(m))
+
+	m.PDUSent(data.SUBMIT_SM)
+	m.PDUSent(data.SUBMIT_SM)
+	m.PDUReceived(data.SUBMIT_SM_RESP)
+	m.ResponseStatus(data.SUBMIT_SM_RESP, data.ESME_RTHROTTLED)
+	m.ResponseLatency(data.SUBMIT_SM_RESP, 20*time.Millisecond)
+	m.WindowSize(3)
+	m.RequestExpired(data.SUBMIT_SM)
+	m.RebindAttempt(errors.New("refused"))
+	m.RebindAttempt(nil)
+	m.EnquireLinkRTT(time.Millisecond)
+
+	require.Equal(t, float64(2), testutil.ToFloat64(m.pduSent.WithLabelValues("SUBMIT_SM")))
+	require.Equal(t, float64(3), testutil.ToFloat64(m.windowSize))
+
+	expected := `
+# HELP smpp_response_status_total Number of responses received from SMSC, by command and status.
+# TYPE smpp_response_status_total counter
+smpp_response_status_total{bind="test",command="SUBMIT_SM_RESP",status="ESME_RTHROTTLED"} 1
+# HELP smpp_rebind_attempts_total Number of rebind attempts, by result.
+# TYPE smpp_rebind_attempts_total counter
+smpp_rebind_attempts_total{bind="test",result="failure"} 1
+smpp_rebind_attempts_total{bind="test",result="success"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"smpp_response_status_total", "smpp_rebind_attempts_total"))
+
+	count, err := testutil.GatherAndCount(registry)
+	require.NoError(t, err)
+	require.Equal(t, 9, count)
+}
@@ -0,0 +1,125 @@
+package gosmpp
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordedMetrics struct {
+	NopMetrics
+
+	mu       sync.Mutex
+	sent     map[data.CommandIDType]int
+	received map[data.CommandIDType]int
+	statuses map[data.CommandStatusType]int
+	latency  int
+	window   []int
+	rtt      int
+}
+
+func newRecordedMetrics() *recordedMetrics {
+	return &recordedMetrics{
+		sent:     make(map[data.CommandIDType]int),
+		received: make(map[data.CommandIDType]int),
+		statuses: make(map[data.CommandStatusType]int),
+	}
+}
+
+func (m *recordedMetrics) PDUSent(id data.CommandIDType) {
+	m.mu.Lock()
+	m.sent[id]++
+	m.mu.Unlock()
+}
+
+func (m *recordedMetrics) PDUReceived(id data.CommandIDType) {
+	m.mu.Lock()
+	m.received[id]++
+	m.mu.Unlock()
+}
+
+func (m *recordedMetrics) ResponseStatus(_ data.CommandIDType, status data.CommandStatusType) {
+	m.mu.Lock()
+	m.statuses[status]++
+	m.mu.Unlock()
+}
+
+func (m *recordedMetrics) ResponseLatency(data.CommandIDType, time.Duration) {
+	m.mu.Lock()
+	m.latency++
+	m.mu.Unlock()
+}
+
+func (m *recordedMetrics) WindowSize(size int) {
+	m.mu.Lock()
+	m.window = append(m.window, size)
+	m.mu.Unlock()
+}
+
+func (m *recordedMetrics) EnquireLinkRTT(time.Duration) {
+	m.mu.Lock()
+	m.rtt++
+	m.mu.Unlock()
+}
+
+func TestMetrics(t *testing.T) {
+	client, server := net.Pipe()
+	go pipeSMSC(server, func(p pdu.PDU) pdu.PDU {
+		switch p.(type) {
+		case *pdu.SubmitSM:
+			resp := p.GetResponse()
+			resp.(*pdu.SubmitSMResp).CommandStatus = data.ESME_RINVDSTADR
+			return resp
+		case *pdu.EnquireLink:
+			return p.GetResponse()
+		}
+		return nil
+	})
+
+	metrics := newRecordedMetrics()
+	responses := make(chan Response, 2)
+	trans := newTransceivable(NewConnection(client), Settings{
+		ReadTimeout: 2 * time.Second,
+		Metrics:     metrics,
+		WindowedRequestTracking: &WindowedRequestTracking{
+			OnExpectedPduResponse: func(r Response) {
+				responses <- r
+			},
+			MaxWindowSize:      5,
+			StoreAccessTimeOut: 100,
+		},
+	}, NewDefaultStore())
+	trans.start()
+	defer func() {
+		_ = trans.Close()
+	}()
+
+	require.NoError(t, trans.Submit(pdu.NewSubmitSM()))
+	<-responses
+	require.NoError(t, trans.Submit(pdu.NewEnquireLink()))
+	<-responses
+
+	// counted once written, might be after the response is read
+	require.Eventually(t, func() bool {
+		metrics.mu.Lock()
+		defer metrics.mu.Unlock()
+		return metrics.sent[data.SUBMIT_SM] == 1 && metrics.sent[data.ENQUIRE_LINK] == 1
+	}, time.Second, 10*time.Millisecond)
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	require.Equal(t, 1, metrics.received[data.SUBMIT_SM_RESP])
+	require.Equal(t, 1, metrics.received[data.ENQUIRE_LINK_RESP])
+	require.Equal(t, 1, metrics.statuses[data.ESME_RINVDSTADR])
+	require.Equal(t, 1, metrics.statuses[data.ESME_ROK])
+	require.Equal(t, 2, metrics.latency)
+	require.Equal(t, 1, metrics.rtt)
+	require.Equal(t, []int{1, 0, 1, 0}, metrics.window)
+}
@@ -0,0 +1,235 @@
+package gosmpp
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// PDUMatcher reports if a received PDU should be handled by a route of PDUMux.
+type PDUMatcher func(p pdu.PDU) bool
+
+type muxRoute struct {
+	matchers    []PDUMatcher
+	handler     AllPDUCallback
+	fallThrough bool
+}
+
+func (r *muxRoute) match(p pdu.PDU) bool {
+	for _, m := range r.matchers {
+		if !m(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// PDUMux routes received PDU to handlers registered by PDU type, message type and address.
+//
+// Routes are tried in the order of registration, the first matching route handles the PDU.
+// A route registered with HandleFallthrough passes the PDU on to the next matching route after handling it.
+// PDU matched by no route goes to the default handler.
+//
+// PDUMux plugs into Settings as callback:
+//
+//	mux := gosmpp.NewPDUMux()
+//	mux.Handle(handleReceipt, gosmpp.MatchDeliveryReceipt())
+//	mux.Handle(handleMO, gosmpp.MatchType(&pdu.DeliverSM{}, &pdu.DataSM{}), gosmpp.MatchDestPrefix("1234"))
+//	mux.Handle(handleAlert, gosmpp.MatchType(&pdu.AlertNotification{}))
+//
+//	settings := gosmpp.Settings{
+//		OnAllPDU: mux.OnAllPDU,
+//	}
+type PDUMux struct {
+	mu     sync.RWMutex
+	routes []muxRoute
+	def    AllPDUCallback
+}
+
+// NewPDUMux creates PDUMux with no route.
+func NewPDUMux() *PDUMux {
+	return &PDUMux{}
+}
+
+// Handle registers handler for PDU matching all the matchers. Route without matcher matches any PDU.
+// Nil handler is ignored.
+func (m *PDUMux) Handle(handler AllPDUCallback, matchers ...PDUMatcher) {
+	m.handle(handler, matchers, false)
+}
+
+// HandleFallthrough registers handler like Handle, but the PDU is also passed to the next matching route
+// or to the default handler.
+func (m *PDUMux) HandleFallthrough(handler AllPDUCallback, matchers ...PDUMatcher) {
+	m.handle(handler, matchers, true)
+}
+
+func (m *PDUMux) handle(handler AllPDUCallback, matchers []PDUMatcher, fallThrough bool) {
+	if handler == nil {
+		return
+	}
+
+	m.mu.Lock()
+	m.routes = append(m.routes, muxRoute{
+		matchers:    append([]PDUMatcher(nil), matchers...),
+		handler:     handler,
+		fallThrough: fallThrough,
+	})
+	m.mu.Unlock()
+}
+
+// HandleDefault registers handler for PDU matched by no route, or passed on by fallthrough routes.
+//
+// Without default handler, such PDU is responded with its generic response
+// and Unbind closes the bind, as done when OnAllPDU is not set.
+func (m *PDUMux) HandleDefault(handler AllPDUCallback) {
+	m.mu.Lock()
+	m.def = handler
+	m.mu.Unlock()
+}
+
+// OnAllPDU routes the PDU and returns response of the handler to be sent back to SMSC.
+//
+// When multiple handlers are called due to fallthrough, the first non-nil response is returned
+// and the bind is closed if any of them asks to.
+//
+// Use as Settings.OnAllPDU or WindowedRequestTracking.OnReceivedPduRequest.
+func (m *PDUMux) OnAllPDU(p pdu.PDU) (resp pdu.PDU, closeBind bool) {
+	m.mu.RLock()
+	routes, def := m.routes, m.def
+	m.mu.RUnlock()
+
+	for i := range routes {
+		if !routes[i].match(p) {
+			continue
+		}
+
+		r, c := routes[i].handler(p)
+		if resp == nil {
+			resp = r
+		}
+		closeBind = closeBind || c
+
+		if !routes[i].fallThrough {
+			return
+		}
+	}
+
+	if def == nil {
+		def = defaultPDUHandler
+	}
+	r, c := def(p)
+	if resp == nil {
+		resp = r
+	}
+	closeBind = closeBind || c
+	return
+}
+
+// OnPDU routes the PDU already responded automatically. Responses of handlers are discarded.
+//
+// Use as Settings.OnPDU.
+func (m *PDUMux) OnPDU(p pdu.PDU, _ bool) {
+	_, _ = m.OnAllPDU(p)
+}
+
+// defaultPDUHandler responds PDU the same way as when no callback is set.
+func defaultPDUHandler(p pdu.PDU) (pdu.PDU, bool) {
+	if _, ok := p.(*pdu.Unbind); ok {
+		return p.GetResponse(), true
+	}
+	if p.CanResponse() {
+		return p.GetResponse(), false
+	}
+	return nil, false
+}
+
+// MatchType matches PDU of the same type as any of samples, e.g. MatchType(&pdu.DeliverSM{}).
+func MatchType(samples ...pdu.PDU) PDUMatcher {
+	types := make([]reflect.Type, len(samples))
+	for i := range samples {
+		types[i] = reflect.TypeOf(samples[i])
+	}
+	return func(p pdu.PDU) bool {
+		t := reflect.TypeOf(p)
+		for i := range types {
+			if t == types[i] {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchDeliveryReceipt matches DeliverSM and DataSM carrying SMSC delivery receipt.
+func MatchDeliveryReceipt() PDUMatcher {
+	return func(p pdu.PDU) bool {
+		esmClass, ok := messageEsmClass(p)
+		return ok && esmClass&data.SM_MSG_TYPE_MASK == data.SM_SMSC_DLV_RCPT_TYPE
+	}
+}
+
+// MatchMO matches DeliverSM and DataSM carrying mobile originated message, i.e. not a delivery receipt
+// or acknowledgement.
+func MatchMO() PDUMatcher {
+	return func(p pdu.PDU) bool {
+		esmClass, ok := messageEsmClass(p)
+		return ok && esmClass&data.SM_MSG_TYPE_MASK == 0
+	}
+}
+
+// MatchDestPrefix matches PDU whose destination address starts with any of prefixes.
+//
+// Destination address is DestAddr of DeliverSM, DataSM and SubmitSM and EsmeAddr of AlertNotification.
+func MatchDestPrefix(prefixes ...string) PDUMatcher {
+	prefixes = append([]string(nil), prefixes...)
+	return func(p pdu.PDU) bool {
+		addr, ok := destAddress(p)
+		if !ok {
+			return false
+		}
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(addr, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchDestRegexp matches PDU whose destination address matches re.
+//
+// Destination address is the same as for MatchDestPrefix.
+func MatchDestRegexp(re *regexp.Regexp) PDUMatcher {
+	return func(p pdu.PDU) bool {
+		addr, ok := destAddress(p)
+		return ok && re.MatchString(addr)
+	}
+}
+
+func messageEsmClass(p pdu.PDU) (byte, bool) {
+	switch pp := p.(type) {
+	case *pdu.DeliverSM:
+		return pp.EsmClass, true
+	case *pdu.DataSM:
+		return pp.EsmClass, true
+	}
+	return 0, false
+}
+
+func destAddress(p pdu.PDU) (string, bool) {
+	switch pp := p.(type) {
+	case *pdu.DeliverSM:
+		return pp.DestAddr.Address(), true
+	case *pdu.DataSM:
+		return pp.DestAddr.Address(), true
+	case *pdu.SubmitSM:
+		return pp.DestAddr.Address(), true
+	case *pdu.AlertNotification:
+		return pp.EsmeAddr.Address(), true
+	}
+	return "", false
+}
@@ -0,0 +1,88 @@
+package gosmpp
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPDUMux(t *testing.T) {
+	newDeliverSM := func(dest string, esmClass byte) *pdu.DeliverSM {
+		p := pdu.NewDeliverSM().(*pdu.DeliverSM)
+		require.NoError(t, p.DestAddr.SetAddress(dest))
+		p.EsmClass = esmClass
+		return p
+	}
+
+	var called []string
+	handler := func(name string, closeBind bool) AllPDUCallback {
+		return func(p pdu.PDU) (pdu.PDU, bool) {
+			called = append(called, name)
+			if p.CanResponse() {
+				return p.GetResponse(), closeBind
+			}
+			return nil, closeBind
+		}
+	}
+
+	mux := NewPDUMux()
+	mux.HandleFallthrough(handler("audit", false), MatchType(&pdu.DeliverSM{}, &pdu.DataSM{}))
+	mux.Handle(handler("receipt", false), MatchDeliveryReceipt())
+	mux.Handle(handler("premium", false), MatchMO(), MatchDestPrefix("900", "901"))
+	mux.Handle(handler("short", false), MatchMO(), MatchDestRegexp(regexp.MustCompile(`^\d{4}$`)))
+	mux.Handle(handler("alert", true), MatchType(&pdu.AlertNotification{}))
+	mux.Handle(nil)
+
+	route := func(p pdu.PDU) (pdu.PDU, bool) {
+		called = called[:0]
+		return mux.OnAllPDU(p)
+	}
+
+	resp, closeBind := route(newDeliverSM("9001234", data.SM_SMSC_DLV_RCPT_TYPE))
+	require.IsType(t, &pdu.DeliverSMResp{}, resp)
+	require.False(t, closeBind)
+	require.Equal(t, []string{"audit", "receipt"}, called)
+
+	_, _ = route(newDeliverSM("9001234", 0))
+	require.Equal(t, []string{"audit", "premium"}, called)
+
+	_, _ = route(newDeliverSM("1234", data.SM_UDH_GSM))
+	require.Equal(t, []string{"audit", "short"}, called)
+
+	dataSM := pdu.NewDataSM().(*pdu.DataSM)
+	dataSM.EsmClass = data.SM_SMSC_DLV_RCPT_TYPE
+	_, _ = route(dataSM)
+	require.Equal(t, []string{"audit", "receipt"}, called)
+
+	_, closeBind = route(pdu.NewAlertNotification())
+	require.True(t, closeBind)
+	require.Equal(t, []string{"alert"}, called)
+
+	// no route, generic handling
+	resp, closeBind = route(newDeliverSM("55555", 0))
+	require.IsType(t, &pdu.DeliverSMResp{}, resp)
+	require.False(t, closeBind)
+	require.Equal(t, []string{"audit"}, called)
+
+	resp, closeBind = route(pdu.NewUnbind())
+	require.IsType(t, &pdu.UnbindResp{}, resp)
+	require.True(t, closeBind)
+	require.Empty(t, called)
+
+	mux.HandleDefault(handler("default", false))
+	_, _ = route(newDeliverSM("55555", 0))
+	require.Equal(t, []string{"audit", "default"}, called)
+
+	resp, closeBind = route(pdu.NewEnquireLink())
+	require.IsType(t, &pdu.EnquireLinkResp{}, resp)
+	require.False(t, closeBind)
+	require.Equal(t, []string{"default"}, called)
+
+	// plugged into Settings
+	_ = Settings{OnAllPDU: mux.OnAllPDU, OnPDU: mux.OnPDU}
+	_ = WindowedRequestTracking{OnReceivedPduRequest: mux.OnAllPDU}
+}
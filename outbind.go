@@ -0,0 +1,113 @@
+package gosmpp
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+var (
+	// ErrOutbindRejected indicates SMSC sent outbind with unexpected SystemID/Password.
+	ErrOutbindRejected = errors.New("outbind rejected: invalid system_id or password")
+	// ErrOutbindExpected indicates SMSC sent other PDU than outbind on a new connection.
+	ErrOutbindExpected = errors.New("outbind expected as first PDU")
+)
+
+const outbindReadTimeout = 10 * time.Second
+
+// OutbindConnector is a Receiver (RX) connector for SMSC initiated sessions.
+//
+// It listens on a local address and waits for SMSC to connect and send outbind.
+// Once the outbind SystemID/Password match Auth, bind_receiver is sent over the same connection.
+// Auth.SMSC is not used.
+type OutbindConnector struct {
+	connector
+	listener net.Listener
+}
+
+// NewOutbindConnector listens on local address `addr` and returns OutbindConnector.
+func NewOutbindConnector(addr string, auth Auth, opts ...connectorOption) (*OutbindConnector, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return NewOutbindConnectorWithListener(l, auth, opts...), nil
+}
+
+// NewOutbindConnectorWithListener returns OutbindConnector accepting SMSC connections from listener.
+//
+// Wrap the listener with tls.NewListener to accept SMSC connecting over TLS.
+func NewOutbindConnectorWithListener(listener net.Listener, auth Auth, opts ...connectorOption) *OutbindConnector {
+	c := &OutbindConnector{
+		connector: connector{
+			auth:        auth,
+			bindingType: pdu.Receiver,
+		},
+		listener: listener,
+	}
+	for _, opt := range opts {
+		opt(&c.connector)
+	}
+	return c
+}
+
+// Connect waits for SMSC to connect and send outbind, then binds as receiver.
+func (c *OutbindConnector) Connect() (conn *Connection, err error) {
+	nc, err := c.listener.Accept()
+	if err != nil {
+		return
+	}
+
+	if err = handshake(nc, outbindReadTimeout); err != nil {
+		_ = nc.Close()
+		return
+	}
+
+	conn = c.newConnection(nc)
+	if err = c.acceptOutbind(conn); err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	return bind(conn, newBindRequest(c.auth, c.bindingType, c.addressRange, c.interfaceVersion))
+}
+
+// Addr returns the listener's network address.
+func (c *OutbindConnector) Addr() net.Addr {
+	return c.listener.Addr()
+}
+
+// Close stops listening. Any blocked Connect will return error.
+func (c *OutbindConnector) Close() error {
+	return c.listener.Close()
+}
+
+func (c *OutbindConnector) acceptOutbind(conn *Connection) (err error) {
+	if err = conn.SetReadTimeout(outbindReadTimeout); err != nil {
+		return
+	}
+
+	p, err := conn.ReadPDU()
+	if isInvalidTLV(p, err) {
+		// malformed optional parameter is dropped, outbind does not depend on it
+		err = nil
+	}
+	if err != nil {
+		return
+	}
+
+	outbind, ok := p.(*pdu.Outbind)
+	switch {
+	case !ok:
+		err = ErrOutbindExpected
+
+	case outbind.SystemID != c.auth.SystemID || outbind.Password != c.auth.Password:
+		err = ErrOutbindRejected
+
+	default:
+		err = conn.SetReadDeadline(time.Time{})
+	}
+	return
+}
@@ -0,0 +1,98 @@
+package gosmpp
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+// outbindSMSC connects to ESME, sends outbind and serves the following bind_receiver.
+func outbindSMSC(t *testing.T, addr, systemID, password string) {
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+
+	c := NewConnection(conn)
+	defer func() {
+		_ = c.Close()
+	}()
+
+	outbind := pdu.NewOutbind().(*pdu.Outbind)
+	outbind.SystemID = systemID
+	outbind.Password = password
+	_, err = c.WritePDU(outbind)
+	require.NoError(t, err)
+
+	p, err := pdu.Parse(c)
+	if err != nil {
+		return // ESME rejected outbind
+	}
+	req, ok := p.(*pdu.BindRequest)
+	require.True(t, ok)
+	require.Equal(t, pdu.Receiver, req.BindingType)
+
+	resp := req.GetResponse().(*pdu.BindResp)
+	resp.SystemID = "GoSMSC"
+	_, err = c.WritePDU(resp)
+	require.NoError(t, err)
+
+	_, err = c.WritePDU(pdu.NewDeliverSM())
+	require.NoError(t, err)
+
+	for {
+		if p, err = pdu.Parse(c); err != nil {
+			return
+		}
+		if _, ok := p.(*pdu.Unbind); ok {
+			return
+		}
+	}
+}
+
+func TestOutbindConnector(t *testing.T) {
+	auth := Auth{SystemID: "esme", Password: "secret"}
+
+	c, err := NewOutbindConnector("127.0.0.1:0", auth)
+	require.NoError(t, err)
+	defer func() {
+		_ = c.Close()
+	}()
+	require.Equal(t, pdu.Receiver, c.GetBindType())
+
+	t.Run("Rejected", func(t *testing.T) {
+		go outbindSMSC(t, c.Addr().String(), "esme", "wrong")
+
+		_, err := c.Connect()
+		require.Equal(t, ErrOutbindRejected, err)
+	})
+
+	t.Run("Session", func(t *testing.T) {
+		go outbindSMSC(t, c.Addr().String(), "esme", "secret")
+
+		var delivered int32
+		session, err := NewSession(c, Settings{
+			ReadTimeout: 2 * time.Second,
+			OnPDU: func(p pdu.PDU, responded bool) {
+				if pd, ok := p.(*pdu.DeliverSM); ok {
+					require.True(t, responded)
+					require.EqualValues(t, data.ESME_ROK, pd.CommandStatus)
+					atomic.AddInt32(&delivered, 1)
+				}
+			},
+		}, -1)
+		require.NoError(t, err)
+		defer func() {
+			_ = session.Close()
+		}()
+
+		require.Equal(t, "GoSMSC", session.Receiver().SystemID())
+		require.Eventually(t, func() bool {
+			return atomic.LoadInt32(&delivered) == 1
+		}, time.Second, 10*time.Millisecond)
+	})
+}
@@ -58,6 +58,12 @@ func NewBindReceiverResp() PDU {
 	return c
 }
 
+// SCInterfaceVersion returns sc_interface_version TLV, the SMPP version supported by SMSC.
+// SMSC supporting SMPP 3.3 only does not return it.
+func (c *BindResp) SCInterfaceVersion() (byte, bool) {
+	return c.GetUint8(TagScInterfaceVersion)
+}
+
 // CanResponse implements PDU interface.
 func (c *BindResp) CanResponse() bool {
 	return false
@@ -71,9 +77,12 @@ func (c *BindResp) GetResponse() PDU {
 // Marshal implements PDU interface.
 func (c *BindResp) Marshal(b *ByteBuffer) {
 	c.base.marshal(b, func(w *ByteBuffer) {
-		w.Grow(len(c.SystemID) + 1)
+		// system_id is omitted on failed binds, mirroring Unmarshal
+		if c.CommandID == data.BIND_TRANSCEIVER_RESP || c.CommandStatus == data.ESME_ROK {
+			w.Grow(len(c.SystemID) + 1)
 
-		_ = w.WriteCString(c.SystemID)
+			_ = w.WriteCString(c.SystemID)
+		}
 	})
 }
 
@@ -53,4 +53,36 @@ func TestBindResponse(t *testing.T) {
 			data.BIND_TRANSCEIVER_RESP,
 		)
 	})
+
+	t.Run("transmitterFailed", func(t *testing.T) {
+		v := NewBindTransmitterResp().(*BindResp)
+		v.SequenceNumber = 13
+		v.CommandStatus = data.ESME_RINVPASWD
+
+		validate(t,
+			v,
+			"00000010800000020000000e0000000d",
+			data.BIND_TRANSMITTER_RESP,
+		)
+	})
+
+	t.Run("scInterfaceVersion", func(t *testing.T) {
+		v := NewBindTransceiverResp().(*BindResp)
+		v.SequenceNumber = 13
+		v.SystemID = "smsc"
+
+		_, ok := v.SCInterfaceVersion()
+		require.False(t, ok)
+
+		v.RegisterOptionalParam(NewUint8Field(TagScInterfaceVersion, data.SMPP_V50))
+		validate(t,
+			v,
+			"0000001a80000009000000000000000d736d7363000210000150",
+			data.BIND_TRANSCEIVER_RESP,
+		)
+
+		version, ok := v.SCInterfaceVersion()
+		require.True(t, ok)
+		require.Equal(t, data.SMPP_V50, version)
+	})
 }
@@ -0,0 +1,119 @@
+package pdu
+
+import (
+	"encoding/binary"
+
+	"github.com/linxGnu/gosmpp/data"
+)
+
+// BroadcastSM PDU (SMPP 5.0) is issued by the ESME to submit a message to the SMSC for broadcast
+// to a specified geographical area or set of geographical areas.
+//
+// The message content is carried by message_payload TLV. Broadcast_area_identifier, broadcast_content_type,
+// broadcast_rep_num and broadcast_frequency_interval TLVs are mandatory.
+type BroadcastSM struct {
+	base
+	ServiceType          string
+	SourceAddr           Address
+	MessageID            string
+	PriorityFlag         byte
+	ScheduleDeliveryTime string
+	ValidityPeriod       string
+	ReplaceIfPresentFlag byte
+	DataCoding           byte
+	SmDefaultMsgID       byte
+}
+
+// NewBroadcastSM returns BroadcastSM PDU.
+func NewBroadcastSM() PDU {
+	c := &BroadcastSM{
+		base:                 newBase(),
+		ServiceType:          data.DFLT_SRVTYPE,
+		SourceAddr:           NewAddress(),
+		MessageID:            data.DFLT_MSGID,
+		PriorityFlag:         data.DFLT_PRIORITY_FLAG,
+		ScheduleDeliveryTime: data.DFLT_SCHEDULE,
+		ValidityPeriod:       data.DFLT_VALIDITY,
+		ReplaceIfPresentFlag: data.DFTL_REPLACE_IFP,
+		DataCoding:           data.DFLT_DATA_CODING,
+		SmDefaultMsgID:       data.DFLT_DFLTMSGID,
+	}
+	c.CommandID = data.BROADCAST_SM
+	return c
+}
+
+// AddBroadcastArea adds broadcast_area_identifier of given format, one of data.BCAST_AREA_FORMAT_*.
+// Multiple areas can be added.
+func (c *BroadcastSM) AddBroadcastArea(format byte, area []byte) {
+	c.AddOptionalParam(NewOctetsField(TagBroadcastAreaIdentifier, append([]byte{format}, area...)))
+}
+
+// SetBroadcastContentType sets broadcast_content_type, network is one of data.BCAST_NETWORK_*.
+func (c *BroadcastSM) SetBroadcastContentType(network byte, contentType uint16) {
+	v := []byte{network, 0, 0}
+	binary.BigEndian.PutUint16(v[1:], contentType)
+	c.RegisterOptionalParam(NewOctetsField(TagBroadcastContentType, v))
+}
+
+// SetBroadcastRepNum sets broadcast_rep_num, number of repeated broadcasts.
+func (c *BroadcastSM) SetBroadcastRepNum(n uint16) {
+	c.RegisterOptionalParam(NewUint16Field(TagBroadcastRepNum, n))
+}
+
+// SetBroadcastFrequencyInterval sets broadcast_frequency_interval, unit is one of data.BCAST_FREQ_*.
+func (c *BroadcastSM) SetBroadcastFrequencyInterval(unit byte, interval uint16) {
+	v := []byte{unit, 0, 0}
+	binary.BigEndian.PutUint16(v[1:], interval)
+	c.RegisterOptionalParam(NewOctetsField(TagBroadcastFrequencyInterval, v))
+}
+
+// CanResponse implements PDU interface.
+func (c *BroadcastSM) CanResponse() bool {
+	return true
+}
+
+// GetResponse implements PDU interface.
+func (c *BroadcastSM) GetResponse() PDU {
+	return NewBroadcastSMRespFromReq(c)
+}
+
+// Marshal implements PDU interface.
+func (c *BroadcastSM) Marshal(b *ByteBuffer) {
+	c.base.marshal(b, func(b *ByteBuffer) {
+		b.Grow(len(c.ServiceType) + len(c.MessageID) + len(c.ScheduleDeliveryTime) + len(c.ValidityPeriod) + 8)
+
+		_ = b.WriteCString(c.ServiceType)
+		c.SourceAddr.Marshal(b)
+		_ = b.WriteCString(c.MessageID)
+		_ = b.WriteByte(c.PriorityFlag)
+		_ = b.WriteCString(c.ScheduleDeliveryTime)
+		_ = b.WriteCString(c.ValidityPeriod)
+		_ = b.WriteByte(c.ReplaceIfPresentFlag)
+		_ = b.WriteByte(c.DataCoding)
+		_ = b.WriteByte(c.SmDefaultMsgID)
+	})
+}
+
+// Unmarshal implements PDU interface.
+func (c *BroadcastSM) Unmarshal(b *ByteBuffer) error {
+	return c.base.unmarshal(b, func(b *ByteBuffer) (err error) {
+		if c.ServiceType, err = b.ReadCString(); err == nil {
+			if err = c.SourceAddr.Unmarshal(b); err == nil {
+				if c.MessageID, err = b.ReadCString(); err == nil {
+					if c.PriorityFlag, err = b.ReadByte(); err == nil {
+						if c.ScheduleDeliveryTime, err = b.ReadCString(); err == nil {
+							if c.ValidityPeriod, err = b.ReadCString(); err == nil {
+								if c.ReplaceIfPresentFlag, err = b.ReadByte(); err == nil {
+									if c.DataCoding, err = b.ReadByte(); err == nil {
+										c.SmDefaultMsgID, err = b.ReadByte()
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+		return
+	})
+}
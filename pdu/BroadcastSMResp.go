@@ -0,0 +1,65 @@
+package pdu
+
+import (
+	"errors"
+	"io"
+
+	"github.com/linxGnu/gosmpp/data"
+)
+
+// BroadcastSMResp PDU (SMPP 5.0).
+//
+// On failure, broadcast_error_status and failed_broadcast_area_identifier TLVs may indicate the reason.
+type BroadcastSMResp struct {
+	base
+	MessageID string
+}
+
+// NewBroadcastSMResp returns new BroadcastSMResp.
+func NewBroadcastSMResp() PDU {
+	c := &BroadcastSMResp{
+		base:      newBase(),
+		MessageID: data.DFLT_MSGID,
+	}
+	c.CommandID = data.BROADCAST_SM_RESP
+	return c
+}
+
+// NewBroadcastSMRespFromReq returns new BroadcastSMResp.
+func NewBroadcastSMRespFromReq(req *BroadcastSM) PDU {
+	c := NewBroadcastSMResp().(*BroadcastSMResp)
+	if req != nil {
+		c.SequenceNumber = req.SequenceNumber
+	}
+	return c
+}
+
+// CanResponse implements PDU interface.
+func (c *BroadcastSMResp) CanResponse() bool {
+	return false
+}
+
+// GetResponse implements PDU interface.
+func (c *BroadcastSMResp) GetResponse() PDU {
+	return nil
+}
+
+// Marshal implements PDU interface.
+func (c *BroadcastSMResp) Marshal(b *ByteBuffer) {
+	c.base.marshal(b, func(b *ByteBuffer) {
+		b.Grow(len(c.MessageID) + 1)
+
+		_ = b.WriteCString(c.MessageID)
+	})
+}
+
+// Unmarshal implements PDU interface.
+func (c *BroadcastSMResp) Unmarshal(b *ByteBuffer) error {
+	return c.base.unmarshal(b, func(b *ByteBuffer) (err error) {
+		c.MessageID, err = b.ReadCString()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return
+	})
+}
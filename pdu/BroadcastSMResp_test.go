@@ -0,0 +1,35 @@
+package pdu
+
+import (
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcastSMResp(t *testing.T) {
+	req := NewBroadcastSM().(*BroadcastSM)
+	req.SequenceNumber = 13
+
+	v := NewBroadcastSMRespFromReq(req).(*BroadcastSMResp)
+	require.False(t, v.CanResponse())
+	require.Nil(t, v.GetResponse())
+
+	v.MessageID = "id"
+	validate(t,
+		v,
+		"0000001380000111000000000000000d696400",
+		data.BROADCAST_SM_RESP,
+	)
+
+	// failed broadcast has no message_id
+	failed := NewBroadcastSMResp().(*BroadcastSMResp)
+	failed.SequenceNumber = 13
+	failed.CommandStatus = data.ESME_RBCASTFAIL
+	failed.CommandLength = 16
+	buf := NewBuffer(fromHex("00000010800001110000010d0000000d"))
+	p, err := Parse(buf)
+	require.NoError(t, err)
+	require.Equal(t, failed, p)
+}
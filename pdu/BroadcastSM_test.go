@@ -0,0 +1,42 @@
+package pdu
+
+import (
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcastSM(t *testing.T) {
+	v := NewBroadcastSM().(*BroadcastSM)
+	require.True(t, v.CanResponse())
+	v.SequenceNumber = 13
+
+	validate(t,
+		v.GetResponse(),
+		"0000001180000111000000000000000d00",
+		data.BROADCAST_SM_RESP,
+	)
+
+	v.ServiceType = "abc"
+	_ = v.SourceAddr.SetAddress("Alicer")
+	v.SourceAddr.SetTon(28)
+	v.SourceAddr.SetNpi(29)
+	v.MessageID = "id"
+	v.PriorityFlag = 1
+	v.DataCoding = data.UCS2Coding
+	v.AddBroadcastArea(data.BCAST_AREA_FORMAT_ALIAS, []byte("north"))
+	v.AddBroadcastArea(data.BCAST_AREA_FORMAT_ALIAS, []byte("south"))
+	v.SetBroadcastContentType(data.BCAST_NETWORK_GSM, 0x0102)
+	v.SetBroadcastRepNum(3)
+	v.SetBroadcastFrequencyInterval(data.BCAST_FREQ_MINUTES, 15)
+	v.RegisterOptionalParam(NewOctetsField(TagMessagePayload, []byte("hi")))
+
+	validate(t,
+		v,
+		"0000005400000111000000000000000d616263001c1d416c696365720069640001000000080006060006006e6f7274680606000600736f757468060100030101020604000200030605000309000f042400026869",
+		data.BROADCAST_SM,
+	)
+	require.Len(t, v.GetOptionalParams(TagBroadcastAreaIdentifier), 2)
+}
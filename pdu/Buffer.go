@@ -32,6 +32,10 @@ var (
 // ByteBuffer wraps over bytes.Buffer with additional features.
 type ByteBuffer struct {
 	*bytes.Buffer
+
+	// OmitOptionalParams drops optional parameters of PDUs marshalled into the buffer,
+	// as SMPP 3.3 does not support them.
+	OmitOptionalParams bool
 }
 
 // NewBuffer create new buffer from preallocated buffer array.
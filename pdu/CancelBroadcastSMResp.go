@@ -0,0 +1,48 @@
+package pdu
+
+import (
+	"github.com/linxGnu/gosmpp/data"
+)
+
+// CancelBroadcastSMResp PDU (SMPP 5.0).
+type CancelBroadcastSMResp struct {
+	base
+}
+
+// NewCancelBroadcastSMResp returns CancelBroadcastSMResp.
+func NewCancelBroadcastSMResp() PDU {
+	c := &CancelBroadcastSMResp{
+		base: newBase(),
+	}
+	c.CommandID = data.CANCEL_BROADCAST_SM_RESP
+	return c
+}
+
+// NewCancelBroadcastSMRespFromReq returns CancelBroadcastSMResp.
+func NewCancelBroadcastSMRespFromReq(req *CancelBroadcastSM) PDU {
+	c := NewCancelBroadcastSMResp().(*CancelBroadcastSMResp)
+	if req != nil {
+		c.SequenceNumber = req.SequenceNumber
+	}
+	return c
+}
+
+// CanResponse implements PDU interface.
+func (c *CancelBroadcastSMResp) CanResponse() bool {
+	return false
+}
+
+// GetResponse implements PDU interface.
+func (c *CancelBroadcastSMResp) GetResponse() PDU {
+	return nil
+}
+
+// Marshal implements PDU interface.
+func (c *CancelBroadcastSMResp) Marshal(b *ByteBuffer) {
+	c.base.marshal(b, nil)
+}
+
+// Unmarshal implements PDU interface.
+func (c *CancelBroadcastSMResp) Unmarshal(b *ByteBuffer) error {
+	return c.base.unmarshal(b, nil)
+}
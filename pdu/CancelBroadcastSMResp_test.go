@@ -0,0 +1,24 @@
+package pdu
+
+import (
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancelBroadcastSMResp(t *testing.T) {
+	req := NewCancelBroadcastSM().(*CancelBroadcastSM)
+	req.SequenceNumber = 13
+
+	v := NewCancelBroadcastSMRespFromReq(req).(*CancelBroadcastSMResp)
+	require.False(t, v.CanResponse())
+	require.Nil(t, v.GetResponse())
+
+	validate(t,
+		v,
+		"0000001080000113000000000000000d",
+		data.CANCEL_BROADCAST_SM_RESP,
+	)
+}
@@ -41,6 +41,30 @@ func (c *DataSM) GetResponse() PDU {
 	return NewDataSMRespFromReq(c)
 }
 
+// SetMessagePayload puts message, with its UDH if any, into message_payload TLV.
+// data_coding and UDH indicator of esm_class are set accordingly.
+func (c *DataSM) SetMessagePayload(sm ShortMessage) {
+	sm.asPayload = true
+	sm.marshalPayload(&c.base)
+
+	if sm.enc == nil {
+		c.DataCoding = data.GSM7BITCoding
+	} else {
+		c.DataCoding = sm.enc.DataCoding()
+	}
+
+	if sm.udHeader.UDHL() > 0 {
+		c.EsmClass |= data.SM_UDH_GSM
+	}
+}
+
+// GetMessagePayload returns message carried by message_payload TLV, with encoding from data_coding.
+func (c *DataSM) GetMessagePayload() (sm ShortMessage, err error) {
+	sm.enc = data.FromDataCoding(c.DataCoding)
+	err = sm.unmarshalPayload(&c.base, (c.EsmClass&data.SM_UDH_GSM) > 0)
+	return
+}
+
 // Marshal implements PDU interface.
 func (c *DataSM) Marshal(b *ByteBuffer) {
 	c.base.marshal(b, func(b *ByteBuffer) {
@@ -46,3 +46,31 @@ func TestDataSM(t *testing.T) {
 		data.DATA_SM,
 	)
 }
+
+func TestDataSMMessagePayload(t *testing.T) {
+	message, err := NewMessagePayloadWithEncoding("hello payload", data.UCS2)
+	require.NoError(t, err)
+	message.SetUDH(UDH{NewIEConcatMessage(2, 2, 1)})
+
+	v := NewDataSM().(*DataSM)
+	v.SetMessagePayload(message)
+	require.Equal(t, data.UCS2Coding, v.DataCoding)
+	require.NotZero(t, v.EsmClass&data.SM_UDH_GSM)
+
+	buf := NewBuffer(nil)
+	v.Marshal(buf)
+	p, err := Parse(buf)
+	require.NoError(t, err)
+
+	sm, err := p.(*DataSM).GetMessagePayload()
+	require.NoError(t, err)
+	require.Equal(t, data.UCS2, sm.Encoding())
+
+	m, err := sm.GetMessage()
+	require.NoError(t, err)
+	require.Equal(t, "hello payload", m)
+
+	_, partNum, _, found := sm.UDH().GetConcatInfo()
+	require.True(t, found)
+	require.Equal(t, byte(2), partNum)
+}
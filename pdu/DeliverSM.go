@@ -54,6 +54,8 @@ func (c *DeliverSM) GetResponse() PDU {
 
 // Marshal implements PDU interface.
 func (c *DeliverSM) Marshal(b *ByteBuffer) {
+	c.Message.marshalPayload(&c.base)
+
 	c.base.marshal(b, func(b *ByteBuffer) {
 		b.Grow(len(c.ServiceType) + len(c.ScheduleDeliveryTime) + len(c.ValidityPeriod) + 10)
 
@@ -72,8 +74,8 @@ func (c *DeliverSM) Marshal(b *ByteBuffer) {
 }
 
 // Unmarshal implements PDU interface.
-func (c *DeliverSM) Unmarshal(b *ByteBuffer) error {
-	return c.base.unmarshal(b, func(b *ByteBuffer) (err error) {
+func (c *DeliverSM) Unmarshal(b *ByteBuffer) (err error) {
+	err = c.base.unmarshal(b, func(b *ByteBuffer) (err error) {
 		if c.ServiceType, err = b.ReadCString(); err == nil {
 			if err = c.SourceAddr.Unmarshal(b); err == nil {
 				if err = c.DestAddr.Unmarshal(b); err == nil {
@@ -97,4 +99,11 @@ func (c *DeliverSM) Unmarshal(b *ByteBuffer) error {
 		}
 		return
 	})
+	// payload is decoded even if another optional parameter is malformed
+	if _, invalidTLV := err.(*InvalidTLVError); err == nil || invalidTLV {
+		if payloadErr := c.Message.unmarshalPayload(&c.base, (c.EsmClass&data.SM_UDH_GSM) > 0); payloadErr != nil {
+			err = payloadErr
+		}
+	}
+	return
 }
@@ -72,3 +72,27 @@ func TestDeliverSMwithUDH(t *testing.T) {
 		data.DELIVER_SM,
 	)
 }
+
+func TestDeliverSMMessagePayload(t *testing.T) {
+	v := NewDeliverSM().(*DeliverSM)
+	v.SequenceNumber = 13
+	v.EsmClass = data.SM_UDH_GSM
+	v.Message.SetUDH(UDH{NewIEConcatMessage(2, 1, 254)})
+	require.NoError(t, v.Message.SetMessagePayloadWithEncoding("nghắ nghiêng nghiễng ngả", data.UCS2))
+
+	buf := NewBuffer(nil)
+	v.Marshal(buf)
+	p, err := Parse(buf)
+	require.NoError(t, err)
+
+	message := p.(*DeliverSM).Message
+	require.True(t, message.IsMessagePayload())
+
+	m, err := message.GetMessage()
+	require.NoError(t, err)
+	require.Equal(t, "nghắ nghiêng nghiễng ngả", m)
+
+	totalParts, partNum, ref, found := message.UDH().GetConcatInfo()
+	require.True(t, found)
+	require.Equal(t, []byte{2, 1, 254}, []byte{totalParts, partNum, ref})
+}
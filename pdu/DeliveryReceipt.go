@@ -0,0 +1,147 @@
+package pdu
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/errors"
+)
+
+// DeliveryReceipt represents SMSC delivery receipt, carried by DeliverSM
+// in the format suggested by SMPP 3.4 Appendix B:
+//
+//	id:IIIIIIIIII sub:SSS dlvrd:DDD submit date:YYMMDDhhmm done date:YYMMDDhhmm stat:DDDDDDD err:E text:...
+type DeliveryReceipt struct {
+	// MessageID is the id returned within submit_sm_resp of the original message.
+	MessageID  string
+	Submitted  int
+	Delivered  int
+	SubmitDate time.Time
+	DoneDate   time.Time
+
+	// Stat is the raw message state, e.g. DELIVRD, UNDELIV.
+	Stat string
+
+	// State is the message state, one of data.SM_STATE_*. Zero if unknown.
+	State byte
+
+	// Err is the network/SMSC specific error code.
+	Err string
+
+	// Text is the first characters of the original message.
+	Text string
+
+	// NetworkErrorCode is the raw value of network_error_code TLV, if any.
+	NetworkErrorCode []byte
+}
+
+var (
+	receiptFieldRegex = regexp.MustCompile(`(?i)(?:^|\s)(id|sub|dlvrd|submit[ _]?date|done[ _]?date|stat|err|text)\s*:`)
+
+	receiptDateLayouts = []string{"0601021504", "060102150405", "20060102150405", "200601021504"}
+
+	receiptStates = map[string]byte{
+		"ENROUTE":       data.SM_STATE_EN_ROUTE,
+		"DELIVRD":       data.SM_STATE_DELIVERED,
+		"DELIVERED":     data.SM_STATE_DELIVERED,
+		"EXPIRED":       data.SM_STATE_EXPIRED,
+		"DELETED":       data.SM_STATE_DELETED,
+		"UNDELIV":       data.SM_STATE_UNDELIVERABLE,
+		"UNDELIVERABLE": data.SM_STATE_UNDELIVERABLE,
+		"ACCEPTD":       data.SM_STATE_ACCEPTED,
+		"ACCEPTED":      data.SM_STATE_ACCEPTED,
+		"UNKNOWN":       data.SM_STATE_INVALID,
+		"REJECTD":       data.SM_STATE_REJECTED,
+		"REJECTED":      data.SM_STATE_REJECTED,
+	}
+)
+
+// IsDeliveryReceipt checks esm_class if this DeliverSM carries SMSC delivery receipt.
+func (c *DeliverSM) IsDeliveryReceipt() bool {
+	return c.EsmClass&data.SM_MSG_TYPE_MASK == data.SM_SMSC_DLV_RCPT_TYPE
+}
+
+// ParseDeliveryReceipt parses delivery receipt from short_message and TLVs.
+//
+// Values from receipted_message_id and message_state TLVs take precedence over the text.
+func (c *DeliverSM) ParseDeliveryReceipt() (r DeliveryReceipt, err error) {
+	if !c.IsDeliveryReceipt() {
+		err = errors.ErrNotDeliveryReceipt
+		return
+	}
+
+	text, e := c.Message.GetMessage()
+	if e != nil {
+		text = string(c.Message.messageData)
+	}
+	r = ParseDeliveryReceiptText(text)
+
+	if id, ok := c.GetCString(TagReceiptedMessageID); ok && id != "" {
+		r.MessageID = id
+	}
+	if state, ok := c.GetUint8(TagMessageStateOption); ok {
+		r.State = state
+	}
+	if code, ok := c.GetOctets(TagNetworkErrorCode); ok {
+		r.NetworkErrorCode = code
+	}
+
+	if r.MessageID == "" {
+		err = errors.ErrInvalidDeliveryReceipt
+	}
+	return
+}
+
+// ParseDeliveryReceiptText parses delivery receipt text, tolerating common vendor variations:
+// case of keys, `submit_date`/`submitdate` spelling, extra whitespaces and missing fields.
+func ParseDeliveryReceiptText(text string) (r DeliveryReceipt) {
+	matches := receiptFieldRegex.FindAllStringSubmatchIndex(text, -1)
+
+	for i, m := range matches {
+		key := strings.ToLower(text[m[2]:m[3]])
+		key = strings.NewReplacer(" ", "", "_", "").Replace(key)
+
+		// text is the last field and may contain anything, including other keys
+		end := len(text)
+		if key != "text" && i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		value := strings.TrimSpace(text[m[1]:end])
+
+		switch key {
+		case "id":
+			r.MessageID = value
+		case "sub":
+			r.Submitted, _ = strconv.Atoi(value)
+		case "dlvrd":
+			r.Delivered, _ = strconv.Atoi(value)
+		case "submitdate":
+			r.SubmitDate = parseReceiptDate(value)
+		case "donedate":
+			r.DoneDate = parseReceiptDate(value)
+		case "stat":
+			r.Stat = value
+			r.State = receiptStates[strings.ToUpper(value)]
+		case "err":
+			r.Err = value
+		case "text":
+			r.Text = text[m[1]:]
+			return
+		}
+	}
+	return
+}
+
+func parseReceiptDate(value string) time.Time {
+	for _, layout := range receiptDateLayouts {
+		if len(layout) == len(value) {
+			if t, err := time.Parse(layout, value); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
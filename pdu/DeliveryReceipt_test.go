@@ -0,0 +1,78 @@
+package pdu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/errors"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliveryReceipt(t *testing.T) {
+	newReceipt := func(text string) *DeliverSM {
+		v := NewDeliverSM().(*DeliverSM)
+		v.EsmClass = data.SM_SMSC_DLV_RCPT_TYPE
+		require.NoError(t, v.Message.SetMessageWithEncoding(text, data.GSM7BIT))
+		return v
+	}
+
+	t.Run("standard", func(t *testing.T) {
+		v := newReceipt("id:0123456789 sub:001 dlvrd:001 submit date:2401021504 done date:2401021505 stat:DELIVRD err:000 text:Hello id:world")
+		require.True(t, v.IsDeliveryReceipt())
+
+		r, err := v.ParseDeliveryReceipt()
+		require.NoError(t, err)
+		require.Equal(t, "0123456789", r.MessageID)
+		require.Equal(t, 1, r.Submitted)
+		require.Equal(t, 1, r.Delivered)
+		require.Equal(t, time.Date(2024, 1, 2, 15, 4, 0, 0, time.UTC), r.SubmitDate)
+		require.Equal(t, time.Date(2024, 1, 2, 15, 5, 0, 0, time.UTC), r.DoneDate)
+		require.Equal(t, "DELIVRD", r.Stat)
+		require.EqualValues(t, data.SM_STATE_DELIVERED, r.State)
+		require.Equal(t, "000", r.Err)
+		require.Equal(t, "Hello id:world", r.Text)
+	})
+
+	t.Run("vendorVariation", func(t *testing.T) {
+		r := ParseDeliveryReceiptText("ID:abc-1  Sub:1 Dlvrd:0 Submit_Date:240102150405 DoneDate:240102150406 Stat:undeliv Err:34 Text:")
+		require.Equal(t, "abc-1", r.MessageID)
+		require.Equal(t, 0, r.Delivered)
+		require.Equal(t, time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC), r.SubmitDate)
+		require.Equal(t, time.Date(2024, 1, 2, 15, 4, 6, 0, time.UTC), r.DoneDate)
+		require.EqualValues(t, data.SM_STATE_UNDELIVERABLE, r.State)
+		require.Equal(t, "34", r.Err)
+		require.Empty(t, r.Text)
+
+		r = ParseDeliveryReceiptText("id:1 stat:FOO submit date:bad")
+		require.Equal(t, "1", r.MessageID)
+		require.Zero(t, r.State)
+		require.True(t, r.SubmitDate.IsZero())
+	})
+
+	t.Run("preferTLV", func(t *testing.T) {
+		v := newReceipt("id:123 stat:ENROUTE")
+		v.RegisterOptionalParam(Field{Tag: TagReceiptedMessageID, Data: []byte("7b\x00")})
+		v.RegisterOptionalParam(Field{Tag: TagMessageStateOption, Data: []byte{data.SM_STATE_EXPIRED}})
+		v.RegisterOptionalParam(Field{Tag: TagNetworkErrorCode, Data: []byte{0x03, 0x00, 0x01}})
+
+		r, err := v.ParseDeliveryReceipt()
+		require.NoError(t, err)
+		require.Equal(t, "7b", r.MessageID)
+		require.EqualValues(t, data.SM_STATE_EXPIRED, r.State)
+		require.Equal(t, "ENROUTE", r.Stat)
+		require.Equal(t, []byte{0x03, 0x00, 0x01}, r.NetworkErrorCode)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		v := newReceipt("id:1")
+		v.EsmClass = data.SM_UDH_GSM
+		require.False(t, v.IsDeliveryReceipt())
+		_, err := v.ParseDeliveryReceipt()
+		require.Equal(t, errors.ErrNotDeliveryReceipt, err)
+
+		_, err = newReceipt("hello").ParseDeliveryReceipt()
+		require.Equal(t, errors.ErrInvalidDeliveryReceipt, err)
+	})
+}
@@ -2,6 +2,7 @@ package pdu
 
 import (
 	"io"
+	"sort"
 
 	"github.com/linxGnu/gosmpp/data"
 	"github.com/linxGnu/gosmpp/errors"
@@ -48,7 +49,12 @@ type PDU interface {
 
 type base struct {
 	Header
+
+	// OptionalParameters holds the latest value of each optional parameter, for lookup by tag.
 	OptionalParameters map[Tag]Field
+
+	// tlvs keeps optional parameters in wire order, including duplicated tags.
+	tlvs []Field
 }
 
 func newBase() (v base) {
@@ -89,7 +95,7 @@ func (c *base) unmarshal(b *ByteBuffer, bodyReader func(*ByteBuffer) error) (err
 				if optParam, err = b.ReadN(cmdLength - got); err == nil {
 					err = c.unmarshalOptionalParam(optParam)
 				}
-				if err != nil {
+				if _, invalidTLV := err.(*InvalidTLVError); err != nil && !invalidTLV {
 					return
 				}
 			}
@@ -104,15 +110,23 @@ func (c *base) unmarshal(b *ByteBuffer, bodyReader func(*ByteBuffer) error) (err
 	return
 }
 
+// unmarshalOptionalParam skips optional parameters violating their spec and keeps decoding the others.
+// The first of them is returned as InvalidTLVError.
 func (c *base) unmarshalOptionalParam(optParam []byte) (err error) {
 	buf := NewBuffer(optParam)
 	for buf.Len() > 0 {
 		var field Field
-		if err = field.Unmarshal(buf); err == nil {
-			c.OptionalParameters[field.Tag] = field
-		} else {
-			return
+		if e := field.Unmarshal(buf); e != nil {
+			return e
+		}
+		if e := field.validate(); e != nil {
+			if err == nil {
+				err = e
+			}
+			continue
 		}
+		c.OptionalParameters[field.Tag] = field
+		c.tlvs = append(c.tlvs, field)
 	}
 	return
 }
@@ -127,8 +141,10 @@ func (c *base) marshal(b *ByteBuffer, bodyWriter func(*ByteBuffer)) {
 	}
 
 	// optional body
-	for _, v := range c.OptionalParameters {
-		v.Marshal(bodyBuf)
+	if !b.OmitOptionalParams {
+		for _, v := range c.OptionalParams() {
+			v.Marshal(bodyBuf)
+		}
 	}
 
 	// write header
@@ -140,8 +156,110 @@ func (c *base) marshal(b *ByteBuffer, bodyWriter func(*ByteBuffer)) {
 }
 
 // RegisterOptionalParam register optional param.
+// Existing value(s) of the same tag are replaced, keeping position of the first one.
 func (c *base) RegisterOptionalParam(tlv Field) {
 	c.OptionalParameters[tlv.Tag] = tlv
+
+	replaced, n := false, 0
+	for _, f := range c.tlvs {
+		if f.Tag != tlv.Tag {
+			c.tlvs[n] = f
+			n++
+		} else if !replaced {
+			c.tlvs[n] = tlv
+			n++
+			replaced = true
+		}
+	}
+	c.tlvs = c.tlvs[:n]
+
+	if !replaced {
+		c.tlvs = append(c.tlvs, tlv)
+	}
+}
+
+// AddOptionalParam appends optional param, keeping existing value(s) of the same tag.
+//
+// Lookup by tag through OptionalParameters returns the latest added value.
+func (c *base) AddOptionalParam(tlv Field) {
+	c.OptionalParameters[tlv.Tag] = tlv
+	c.tlvs = append(c.tlvs, tlv)
+}
+
+// RemoveOptionalParam removes all values of optional param.
+func (c *base) RemoveOptionalParam(tag Tag) {
+	delete(c.OptionalParameters, tag)
+
+	n := 0
+	for _, f := range c.tlvs {
+		if f.Tag != tag {
+			c.tlvs[n] = f
+			n++
+		}
+	}
+	c.tlvs = c.tlvs[:n]
+}
+
+// GetOptionalParams returns all values of optional param in wire order.
+func (c *base) GetOptionalParams(tag Tag) (fields []Field) {
+	for _, f := range c.OptionalParams() {
+		if f.Tag == tag {
+			fields = append(fields, f)
+		}
+	}
+	return
+}
+
+// OptionalParams returns optional params in wire order, including duplicated tags.
+//
+// Changes made directly to OptionalParameters map are taken into account:
+// deleted tags are skipped, the last value of a tag is taken from the map,
+// and tags only present in the map are appended in ascending order.
+func (c *base) OptionalParams() []Field {
+	last := make(map[Tag]int, len(c.tlvs))
+	for i, f := range c.tlvs {
+		last[f.Tag] = i
+	}
+
+	fields := make([]Field, 0, len(c.OptionalParameters))
+	for i, f := range c.tlvs {
+		if v, ok := c.OptionalParameters[f.Tag]; ok {
+			if last[f.Tag] == i {
+				f = v
+			}
+			fields = append(fields, f)
+		}
+	}
+
+	n := len(fields)
+	for tag, v := range c.OptionalParameters {
+		if _, ok := last[tag]; !ok {
+			fields = append(fields, v)
+		}
+	}
+	added := fields[n:]
+	sort.Slice(added, func(i, j int) bool {
+		return added[i].Tag < added[j].Tag
+	})
+
+	return fields
+}
+
+// segment returns copy of base for segment `seq` (1-based) of a long message,
+// with SAR TLVs set when mode is ConcatSAR.
+func (c *base) segment(mode ConcatMode, ref uint16, total, seq int) (b base) {
+	b.Header = c.Header
+	b.OptionalParameters = make(map[Tag]Field, len(c.OptionalParameters)+3)
+	for _, field := range c.OptionalParams() {
+		b.AddOptionalParam(field)
+	}
+
+	if mode == ConcatSAR && total > 1 {
+		b.RegisterOptionalParam(NewUint16Field(TagSarMsgRefNum, ref))
+		b.RegisterOptionalParam(NewUint8Field(TagSarTotalSegments, byte(total)))
+		b.RegisterOptionalParam(NewUint8Field(TagSarSegmentSeqnum, byte(seq)))
+	}
+	return
 }
 
 // IsOk is status ok.
@@ -155,7 +273,21 @@ func (c *base) IsGNack() bool {
 }
 
 // Parse PDU from reader.
+//
+// PDU with malformed optional parameter is returned along with InvalidTLVError.
 func Parse(r io.Reader) (pdu PDU, err error) {
+	var frame []byte
+	if frame, err = ReadFrame(r); err == nil {
+		pdu, err = ParseFrame(frame)
+	}
+	return
+}
+
+// ReadFrame reads raw PDU, header included, as it is on the wire.
+//
+// On error, octets read so far are returned too: the header along with ErrInvalidPDU
+// for invalid command_length, the header and part of the body if reading the body failed.
+func ReadFrame(r io.Reader) (frame []byte, err error) {
 	var headerBytes [16]byte
 
 	if _, err = io.ReadFull(r, headerBytes[:]); err != nil {
@@ -164,25 +296,37 @@ func Parse(r io.Reader) (pdu PDU, err error) {
 
 	header := ParseHeader(headerBytes)
 	if header.CommandLength < 16 || header.CommandLength > data.MAX_PDU_LEN {
-		err = errors.ErrInvalidPDU
-		return
+		return headerBytes[:], errors.ErrInvalidPDU
 	}
 
 	// read pdu body
-	bodyBytes := make([]byte, header.CommandLength-16)
-	if len(bodyBytes) > 0 {
-		if _, err = io.ReadFull(r, bodyBytes); err != nil {
-			return
-		}
+	frame = make([]byte, header.CommandLength)
+	copy(frame, headerBytes[:])
+
+	var n int
+	n, err = io.ReadFull(r, frame[16:])
+	if err != nil {
+		frame = frame[:16+n]
+	}
+	return
+}
+
+// ParseFrame parses PDU from raw frame read by ReadFrame.
+//
+// PDU with malformed optional parameter is returned along with InvalidTLVError.
+func ParseFrame(frame []byte) (pdu PDU, err error) {
+	if len(frame) < 16 {
+		err = errors.ErrInvalidPDU
+		return
 	}
 
+	var headerBytes [16]byte
+	copy(headerBytes[:], frame)
+
 	// try to create pdu
-	if pdu, err = CreatePDUFromCmdID(header.CommandID); err == nil {
-		buf := NewBuffer(make([]byte, 0, header.CommandLength))
-		_, _ = buf.Write(headerBytes[:])
-		if len(bodyBytes) > 0 {
-			_, _ = buf.Write(bodyBytes)
-		}
+	if pdu, err = CreatePDUFromCmdID(ParseHeader(headerBytes).CommandID); err == nil {
+		buf := NewBuffer(make([]byte, 0, len(frame)))
+		_, _ = buf.Write(frame)
 		err = pdu.Unmarshal(buf)
 	}
 
@@ -35,6 +35,14 @@ var pduMap = map[data.CommandIDType]pduGenerator{
 	data.ENQUIRE_LINK_RESP:     NewEnquireLinkResp,
 	data.ALERT_NOTIFICATION:    NewAlertNotification,
 	data.GENERIC_NACK:          NewGenericNack,
+
+	// SMPP 5.0
+	data.BROADCAST_SM:             NewBroadcastSM,
+	data.BROADCAST_SM_RESP:        NewBroadcastSMResp,
+	data.QUERY_BROADCAST_SM:       NewQueryBroadcastSM,
+	data.QUERY_BROADCAST_SM_RESP:  NewQueryBroadcastSMResp,
+	data.CANCEL_BROADCAST_SM:      NewCancelBroadcastSM,
+	data.CANCEL_BROADCAST_SM_RESP: NewCancelBroadcastSMResp,
 }
 
 // CreatePDUFromCmdID creates PDU from cmd id.
@@ -2,20 +2,53 @@ package pdu
 
 import (
 	"encoding/binary"
+	"math"
 	"sync/atomic"
 
 	"github.com/linxGnu/gosmpp/data"
 )
 
+// nextSequenceNumber increments s within allowed range 0x00000001 to 0x7FFFFFFF,
+// wrapping around to 0x00000001 after 0x7FFFFFFF as required by SMPP 3.4.
 func nextSequenceNumber(s *int32) (v int32) {
-	// & 0x7FFFFFFF: cater for integer overflow
-	// Allowed range is 0x01 to 0x7FFFFFFF. This
-	// will still result in a single invalid value
-	// of 0x00 every ~2 billion PDUs (not too bad):
-	if v = atomic.AddInt32(s, 1) & 0x7FFFFFFF; v <= 0 {
-		v = 1
+	for {
+		last := atomic.LoadInt32(s)
+		if v = last + 1; last <= 0 || last == math.MaxInt32 {
+			v = 1
+		}
+		if atomic.CompareAndSwapInt32(s, last, v) {
+			return
+		}
 	}
-	return
+}
+
+// SequenceGenerator generates sequence numbers for request PDUs.
+//
+// Implementation must be safe for concurrent use.
+type SequenceGenerator interface {
+	// Next returns the next sequence number, in range 0x00000001 to 0x7FFFFFFF.
+	Next() int32
+}
+
+// AtomicSequenceGenerator is a lock-free SequenceGenerator. Zero value starts from 1.
+type AtomicSequenceGenerator struct {
+	last int32
+}
+
+// NewAtomicSequenceGenerator returns generator continuing after `last`,
+// e.g. sequence number restored from persistent storage.
+func NewAtomicSequenceGenerator(last int32) *AtomicSequenceGenerator {
+	return &AtomicSequenceGenerator{last: last}
+}
+
+// Next returns the next sequence number.
+func (g *AtomicSequenceGenerator) Next() int32 {
+	return nextSequenceNumber(&g.last)
+}
+
+// Last returns the last generated sequence number.
+func (g *AtomicSequenceGenerator) Last() int32 {
+	return atomic.LoadInt32(&g.last)
 }
 
 // Header represents PDU header.
@@ -52,11 +85,12 @@ func (c *Header) Unmarshal(b *ByteBuffer) (err error) {
 	return
 }
 
-var sequenceNumber int32
+// defaultSequenceGenerator is shared by all PDUs created in the process.
+var defaultSequenceGenerator AtomicSequenceGenerator
 
-// AssignSequenceNumber assigns sequence number auto-incrementally.
+// AssignSequenceNumber assigns sequence number auto-incrementally, from the process-wide generator.
 func (c *Header) AssignSequenceNumber() {
-	c.SetSequenceNumber(nextSequenceNumber(&sequenceNumber))
+	c.SetSequenceNumber(defaultSequenceGenerator.Next())
 }
 
 // ResetSequenceNumber resets sequence number.
@@ -74,6 +108,11 @@ func (c *Header) SetSequenceNumber(v int32) {
 	c.SequenceNumber = v
 }
 
+// SetCommandStatus sets command status, e.g. of a response.
+func (c *Header) SetCommandStatus(v data.CommandStatusType) {
+	c.CommandStatus = v
+}
+
 // Marshal to buffer.
 func (c *Header) Marshal(b *ByteBuffer) {
 	b.Grow(16)
@@ -11,3 +11,14 @@ func TestNextSeq(t *testing.T) {
 	var v int32 = math.MaxInt32
 	require.EqualValues(t, 1, nextSequenceNumber(&v))
 }
+
+func TestAtomicSequenceGenerator(t *testing.T) {
+	var g AtomicSequenceGenerator
+	require.EqualValues(t, 1, g.Next())
+	require.EqualValues(t, 2, g.Next())
+	require.EqualValues(t, 2, g.Last())
+
+	g2 := NewAtomicSequenceGenerator(math.MaxInt32 - 1)
+	require.EqualValues(t, math.MaxInt32, g2.Next())
+	require.EqualValues(t, 1, g2.Next())
+}
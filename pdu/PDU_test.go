@@ -37,6 +37,22 @@ func TestParsePDU(t *testing.T) {
 		require.Equal(t, errors.ErrInvalidPDU, err)
 	})
 
+	t.Run("frame", func(t *testing.T) {
+		raw := fromHex("0000001e00000003000000000000000161776179001c1d416c6963657200")
+		frame, err := ReadFrame(NewBuffer(raw))
+		require.NoError(t, err)
+		require.Equal(t, raw, frame)
+
+		// header is kept for tracing
+		frame, err = ReadFrame(NewBuffer(fromHex("0000000f800000060000000000000001")))
+		require.Equal(t, errors.ErrInvalidPDU, err)
+		require.Len(t, frame, 16)
+
+		frame, err = ReadFrame(NewBuffer(raw[:20]))
+		require.Error(t, err)
+		require.Equal(t, raw[:20], frame)
+	})
+
 	t.Run("invalidBody", func(t *testing.T) {
 		buf := NewBuffer(fromHex("0000001e00000003000000000000000161776179001c1d416c69636572"))
 		_, err := Parse(buf)
@@ -57,3 +73,70 @@ func TestParsePDU(t *testing.T) {
 		}))
 	})
 }
+
+func TestOptionalParamsOrder(t *testing.T) {
+	// enquire_link_resp carrying 0x0204 (2 bytes), 0x020a (2 bytes), 0x0204 (2 bytes) in this order
+	hexValue := "00000022800000150000000000000001" +
+		"020400020001" + "020a00020b84" + "020400020002"
+
+	p, err := Parse(NewBuffer(fromHex(hexValue)))
+	require.NoError(t, err)
+
+	resp := p.(*EnquireLinkResp)
+	require.Equal(t, []Field{
+		NewUint16Field(TagUserMessageReference, 1),
+		NewUint16Field(TagSourcePort, 2948),
+		NewUint16Field(TagUserMessageReference, 2),
+	}, resp.OptionalParams())
+	require.Len(t, resp.GetOptionalParams(TagUserMessageReference), 2)
+
+	// map-style lookup returns the latest value
+	ref, ok := resp.GetUint16(TagUserMessageReference)
+	require.True(t, ok)
+	require.Equal(t, uint16(2), ref)
+
+	// wire order and duplicates survive marshalling
+	buf := NewBuffer(nil)
+	resp.Marshal(buf)
+	require.Equal(t, hexValue, toHex(buf.Bytes()))
+
+	t.Run("mutation", func(t *testing.T) {
+		resp.OptionalParameters[TagDestinationPort] = NewUint16Field(TagDestinationPort, 1)
+		resp.OptionalParameters[TagLanguageIndicator] = NewUint8Field(TagLanguageIndicator, 1)
+		resp.OptionalParameters[TagUserMessageReference] = NewUint16Field(TagUserMessageReference, 3)
+		require.Equal(t, []Field{
+			NewUint16Field(TagUserMessageReference, 1),
+			NewUint16Field(TagSourcePort, 2948),
+			NewUint16Field(TagUserMessageReference, 3),
+			NewUint16Field(TagDestinationPort, 1),
+			NewUint8Field(TagLanguageIndicator, 1),
+		}, resp.OptionalParams())
+
+		resp.RegisterOptionalParam(NewUint16Field(TagUserMessageReference, 4))
+		resp.RemoveOptionalParam(TagDestinationPort)
+		delete(resp.OptionalParameters, TagSourcePort)
+		require.Equal(t, []Field{
+			NewUint16Field(TagUserMessageReference, 4),
+			NewUint8Field(TagLanguageIndicator, 1),
+		}, resp.OptionalParams())
+
+		resp.AddOptionalParam(NewUint16Field(TagUserMessageReference, 5))
+		require.Len(t, resp.GetOptionalParams(TagUserMessageReference), 2)
+	})
+}
+
+func TestOmitOptionalParams(t *testing.T) {
+	v := NewSubmitSMResp().(*SubmitSMResp)
+	v.SequenceNumber = 13
+	v.MessageID = "id"
+	v.RegisterOptionalParam(NewUint16Field(TagUserMessageReference, 1))
+
+	buf := NewBuffer(nil)
+	v.Marshal(buf)
+	require.Equal(t, "0000001980000004000000000000000d696400"+"020400020001", toHex(buf.Bytes()))
+
+	buf = NewBuffer(nil)
+	buf.OmitOptionalParams = true
+	v.Marshal(buf)
+	require.Equal(t, "0000001380000004000000000000000d696400", toHex(buf.Bytes()))
+}
@@ -0,0 +1,70 @@
+package pdu
+
+import (
+	"errors"
+	"io"
+
+	"github.com/linxGnu/gosmpp/data"
+)
+
+// QueryBroadcastSMResp PDU (SMPP 5.0).
+//
+// Message_state, broadcast_area_identifier and broadcast_area_success TLVs are mandatory.
+type QueryBroadcastSMResp struct {
+	base
+	MessageID string
+}
+
+// NewQueryBroadcastSMResp returns new QueryBroadcastSMResp PDU.
+func NewQueryBroadcastSMResp() PDU {
+	c := &QueryBroadcastSMResp{
+		base:      newBase(),
+		MessageID: data.DFLT_MSGID,
+	}
+	c.CommandID = data.QUERY_BROADCAST_SM_RESP
+	return c
+}
+
+// NewQueryBroadcastSMRespFromReq returns new QueryBroadcastSMResp PDU.
+func NewQueryBroadcastSMRespFromReq(req *QueryBroadcastSM) PDU {
+	c := NewQueryBroadcastSMResp().(*QueryBroadcastSMResp)
+	if req != nil {
+		c.SequenceNumber = req.SequenceNumber
+	}
+	return c
+}
+
+// MessageState returns message_state TLV, one of data.SM_STATE_*.
+func (c *QueryBroadcastSMResp) MessageState() (byte, bool) {
+	return c.GetUint8(TagMessageStateOption)
+}
+
+// CanResponse implements PDU interface.
+func (c *QueryBroadcastSMResp) CanResponse() bool {
+	return false
+}
+
+// GetResponse implements PDU interface.
+func (c *QueryBroadcastSMResp) GetResponse() PDU {
+	return nil
+}
+
+// Marshal implements PDU interface.
+func (c *QueryBroadcastSMResp) Marshal(b *ByteBuffer) {
+	c.base.marshal(b, func(b *ByteBuffer) {
+		b.Grow(len(c.MessageID) + 1)
+
+		_ = b.WriteCString(c.MessageID)
+	})
+}
+
+// Unmarshal implements PDU interface.
+func (c *QueryBroadcastSMResp) Unmarshal(b *ByteBuffer) error {
+	return c.base.unmarshal(b, func(b *ByteBuffer) (err error) {
+		c.MessageID, err = b.ReadCString()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return
+	})
+}
@@ -0,0 +1,36 @@
+package pdu
+
+import (
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryBroadcastSMResp(t *testing.T) {
+	req := NewQueryBroadcastSM().(*QueryBroadcastSM)
+	req.SequenceNumber = 13
+
+	v := NewQueryBroadcastSMRespFromReq(req).(*QueryBroadcastSMResp)
+	require.False(t, v.CanResponse())
+	require.Nil(t, v.GetResponse())
+
+	_, ok := v.MessageState()
+	require.False(t, ok)
+
+	v.MessageID = "away"
+	v.RegisterOptionalParam(NewUint8Field(TagMessageStateOption, data.SM_STATE_DELIVERED))
+	v.AddOptionalParam(NewOctetsField(TagBroadcastAreaIdentifier, []byte{data.BCAST_AREA_FORMAT_ALIAS, 'n'}))
+	v.AddOptionalParam(NewUint8Field(TagBroadcastAreaSuccess, 80))
+
+	validate(t,
+		v,
+		"0000002580000112000000000000000d6177617900042700010206060002006e0608000150",
+		data.QUERY_BROADCAST_SM_RESP,
+	)
+
+	state, ok := v.MessageState()
+	require.True(t, ok)
+	require.EqualValues(t, data.SM_STATE_DELIVERED, state)
+}
@@ -0,0 +1,33 @@
+package pdu
+
+import (
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryBroadcastSM(t *testing.T) {
+	v := NewQueryBroadcastSM().(*QueryBroadcastSM)
+	require.True(t, v.CanResponse())
+	v.SequenceNumber = 13
+
+	validate(t,
+		v.GetResponse(),
+		"0000001180000112000000000000000d00",
+		data.QUERY_BROADCAST_SM_RESP,
+	)
+
+	v.MessageID = "away"
+	_ = v.SourceAddr.SetAddress("Alicer")
+	v.SourceAddr.SetTon(28)
+	v.SourceAddr.SetNpi(29)
+	v.RegisterOptionalParam(NewUint16Field(TagUserMessageReference, 7))
+
+	validate(t,
+		v,
+		"0000002400000112000000000000000d61776179001c1d416c6963657200020400020007",
+		data.QUERY_BROADCAST_SM,
+	)
+}
@@ -1,6 +1,9 @@
 package pdu
 
 import (
+	"errors"
+	"io"
+
 	"github.com/linxGnu/gosmpp/data"
 )
 
@@ -57,6 +60,9 @@ func (c *QuerySMResp) Marshal(b *ByteBuffer) {
 }
 
 // Unmarshal implements PDU interface.
+//
+// Truncated body is tolerated, as SMPP 3.3 SMSCs answer failed query_sm without body
+// or without trailing fields.
 func (c *QuerySMResp) Unmarshal(b *ByteBuffer) error {
 	return c.base.unmarshal(b, func(b *ByteBuffer) (err error) {
 		if c.MessageID, err = b.ReadCString(); err == nil {
@@ -66,6 +72,9 @@ func (c *QuerySMResp) Unmarshal(b *ByteBuffer) error {
 				}
 			}
 		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
 		return
 	})
 }
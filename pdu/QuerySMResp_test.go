@@ -21,4 +21,14 @@ func TestQuerySMResp(t *testing.T) {
 		"0000001480000003000000000000000d00000000",
 		data.QUERY_SM_RESP,
 	)
+
+	// SMPP 3.3 SMSC answers failed query_sm without body
+	p, err := Parse(NewBuffer(fromHex("0000001080000003000000670000000d")))
+	require.NoError(t, err)
+	require.Equal(t, data.ESME_RQUERYFAIL, p.GetHeader().CommandStatus)
+	require.Empty(t, p.(*QuerySMResp).MessageID)
+
+	p, err = Parse(NewBuffer(fromHex("0000001480000003000000000000000d69643100")))
+	require.NoError(t, err)
+	require.Equal(t, "id1", p.(*QuerySMResp).MessageID)
 }
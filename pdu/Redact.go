@@ -0,0 +1,161 @@
+package pdu
+
+import "bytes"
+
+// redactionMask replaces redacted octets, keeping lengths so that hex dump layout stays intact.
+const redactionMask = '*'
+
+// Redacted returns a copy of PDU with password and/or message content masked.
+//
+// Password of bind and outbind are masked when `password` is set. Short message and
+// message_payload TLV are masked when `message` is set. Other PDUs are returned as is.
+func Redacted(p PDU, password, message bool) PDU {
+	switch v := p.(type) {
+	case *BindRequest:
+		if password {
+			c := *v
+			c.Password = mask(v.Password)
+			return &c
+		}
+
+	case *Outbind:
+		if password {
+			c := *v
+			c.Password = mask(v.Password)
+			return &c
+		}
+
+	case *SubmitSM:
+		if message {
+			c := *v
+			c.base = v.base.redacted()
+			c.Message = v.Message.redacted()
+			return &c
+		}
+
+	case *SubmitMulti:
+		if message {
+			c := *v
+			c.base = v.base.redacted()
+			c.Message = v.Message.redacted()
+			return &c
+		}
+
+	case *DeliverSM:
+		if message {
+			c := *v
+			c.base = v.base.redacted()
+			c.Message = v.Message.redacted()
+			return &c
+		}
+
+	case *ReplaceSM:
+		if message {
+			c := *v
+			c.base = v.base.redacted()
+			c.Message = v.Message.redacted()
+			return &c
+		}
+
+	case *DataSM:
+		if message {
+			c := *v
+			c.base = v.base.redacted()
+			return &c
+		}
+
+	case *BroadcastSM:
+		if message {
+			c := *v
+			c.base = v.base.redacted()
+			return &c
+		}
+	}
+	return p
+}
+
+// RedactFrame returns a copy of raw frame with the same content masked as Redacted does,
+// p being the PDU parsed from the frame. The frame is returned as is if nothing is masked.
+//
+// If masked octets can not be located, e.g. the frame failed to parse, the whole body
+// of PDU which might carry redacted content is masked, keeping the header.
+func RedactFrame(frame []byte, p PDU, password, message bool) []byte {
+	if len(frame) <= 16 || (!password && !message) {
+		return frame
+	}
+
+	if p == nil {
+		var header [16]byte
+		copy(header[:], frame)
+
+		// unknown command is masked too, as its content is unknown
+		var err error
+		if p, err = CreatePDUFromCmdID(ParseHeader(header).CommandID); err == nil && Redacted(p, password, message) == p {
+			return frame
+		}
+		return maskBody(frame)
+	}
+
+	redacted := Redacted(p, password, message)
+	if redacted == p {
+		return frame
+	}
+
+	// masking keeps lengths, so masked octets are where re-encoded redacted copy differs
+	buf := NewBuffer(make([]byte, 0, len(frame)))
+	redacted.Marshal(buf)
+	encoded := buf.Bytes()
+	if len(encoded) != len(frame) {
+		return maskBody(frame)
+	}
+
+	masked := append([]byte(nil), frame...)
+	for i := 16; i < len(masked); i++ {
+		if encoded[i] == redactionMask {
+			masked[i] = redactionMask
+		}
+	}
+	return masked
+}
+
+// maskBody returns a copy of frame with everything but the header masked.
+func maskBody(frame []byte) []byte {
+	masked := append([]byte(nil), frame...)
+	for i := 16; i < len(masked); i++ {
+		masked[i] = redactionMask
+	}
+	return masked
+}
+
+func mask(s string) string {
+	return string(bytes.Repeat([]byte{redactionMask}, len(s)))
+}
+
+// redacted returns copy of base with message_payload TLV masked.
+func (c *base) redacted() (b base) {
+	b.Header = c.Header
+	b.OptionalParameters = make(map[Tag]Field, len(c.OptionalParameters))
+	for tag, field := range c.OptionalParameters {
+		b.OptionalParameters[tag] = field
+	}
+	b.tlvs = append([]Field(nil), c.tlvs...)
+
+	for i, field := range b.tlvs {
+		if field.Tag == TagMessagePayload {
+			b.tlvs[i].Data = bytes.Repeat([]byte{redactionMask}, len(field.Data))
+		}
+	}
+	if field, ok := b.OptionalParameters[TagMessagePayload]; ok {
+		field.Data = bytes.Repeat([]byte{redactionMask}, len(field.Data))
+		b.OptionalParameters[TagMessagePayload] = field
+	}
+	return
+}
+
+// redacted returns copy of short message with message data masked, keeping UDH.
+func (c *ShortMessage) redacted() (m ShortMessage) {
+	m = *c
+	m.message = ""
+	m.messageData = bytes.Repeat([]byte{redactionMask}, len(c.messageData))
+	return
+}
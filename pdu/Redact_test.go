@@ -0,0 +1,86 @@
+package pdu
+
+import (
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedacted(t *testing.T) {
+	t.Run("password", func(t *testing.T) {
+		b := NewBindRequest(Transceiver)
+		b.SystemID = "esme"
+		b.Password = "secret"
+
+		r := Redacted(b, true, true).(*BindRequest)
+		require.Equal(t, "******", r.Password)
+		require.Equal(t, "esme", r.SystemID)
+		require.Equal(t, "secret", b.Password)
+
+		require.Same(t, b, Redacted(b, false, true))
+	})
+
+	t.Run("message", func(t *testing.T) {
+		s := NewSubmitSM().(*SubmitSM)
+		require.NoError(t, s.Message.SetMessageWithEncoding("hello", data.GSM7BIT))
+		s.RegisterOptionalParam(Field{Tag: TagMessagePayload, Data: []byte("world")})
+		s.RegisterOptionalParam(NewUint16Field(TagSourcePort, 1))
+
+		r := Redacted(s, true, true).(*SubmitSM)
+		od, err := s.Message.GetMessageData()
+		require.NoError(t, err)
+		rd, err := r.Message.GetMessageData()
+		require.NoError(t, err)
+		require.Len(t, rd, len(od))
+		require.NotEqual(t, od, rd)
+		require.Equal(t, []byte("*****"), r.OptionalParameters[TagMessagePayload].Data)
+
+		// original is untouched and both marshal to the same length
+		m, err := s.Message.GetMessage()
+		require.NoError(t, err)
+		require.Equal(t, "hello", m)
+		require.Equal(t, []byte("world"), s.OptionalParameters[TagMessagePayload].Data)
+
+		b1, b2 := NewBuffer(nil), NewBuffer(nil)
+		s.Marshal(b1)
+		r.Marshal(b2)
+		require.Equal(t, b1.Len(), b2.Len())
+		require.NotContains(t, b2.String(), "world")
+	})
+
+	t.Run("broadcast", func(t *testing.T) {
+		b := NewBroadcastSM().(*BroadcastSM)
+		b.RegisterOptionalParam(Field{Tag: TagMessagePayload, Data: []byte("alert")})
+
+		r := Redacted(b, false, true).(*BroadcastSM)
+		require.Equal(t, []byte("*****"), r.OptionalParameters[TagMessagePayload].Data)
+		require.Equal(t, []byte("alert"), b.OptionalParameters[TagMessagePayload].Data)
+	})
+}
+
+func TestRedactFrame(t *testing.T) {
+	b := NewBindRequest(Transceiver)
+	b.SystemID = "esme"
+	b.Password = "secret"
+	buf := NewBuffer(nil)
+	b.Marshal(buf)
+	frame := buf.Bytes()
+
+	p, err := ParseFrame(frame)
+	require.NoError(t, err)
+
+	masked := RedactFrame(frame, p, true, false)
+	require.Len(t, masked, len(frame))
+	require.NotContains(t, string(masked), "secret")
+	require.Contains(t, string(masked), "esme")
+	require.Contains(t, string(frame), "secret")
+
+	require.Equal(t, frame, RedactFrame(frame, p, false, true))
+
+	// body can not be located without PDU
+	masked = RedactFrame(frame, nil, true, false)
+	require.Equal(t, frame[:16], masked[:16])
+	require.NotContains(t, string(masked), "esme")
+}
@@ -9,6 +9,33 @@ import (
 
 var ref = uint32(0)
 
+// ConcatMode indicates how segments of a long message are linked together.
+type ConcatMode byte
+
+const (
+	// ConcatUDH8BitRef links segments with concatenation IE (0x00) in UDH, using 8-bit reference number.
+	ConcatUDH8BitRef ConcatMode = iota
+
+	// ConcatUDH16BitRef links segments with concatenation IE (0x08) in UDH, using 16-bit reference number.
+	ConcatUDH16BitRef
+
+	// ConcatSAR sends segments without UDH, linked by sar_msg_ref_num,
+	// sar_total_segments and sar_segment_seqnum TLVs of the PDU.
+	ConcatSAR
+)
+
+// udhLength returns octets reserved in each segment for UDH.
+func (m ConcatMode) udhLength() int {
+	switch m {
+	case ConcatUDH16BitRef:
+		return 7 // UDHL + IE id + IE length + 4 octets of data
+	case ConcatSAR:
+		return 0
+	default:
+		return 6 // UDHL + IE id + IE length + 3 octets of data
+	}
+}
+
 // ShortMessage message.
 type ShortMessage struct {
 	SmDefaultMsgID    byte
@@ -17,6 +44,7 @@ type ShortMessage struct {
 	udHeader          UDH
 	messageData       []byte
 	withoutDataCoding bool // purpose of ReplaceSM usage
+	asPayload         bool // message is carried by message_payload TLV
 }
 
 // NewShortMessage returns new ShortMessage.
@@ -43,6 +71,19 @@ func NewBinaryShortMessageWithEncoding(messageData []byte, enc data.Encoding) (s
 	return
 }
 
+// NewMessagePayloadWithEncoding returns new ShortMessage carried by message_payload TLV
+// instead of short_message, allowing up to 64K of encoded data.
+func NewMessagePayloadWithEncoding(message string, enc data.Encoding) (s ShortMessage, err error) {
+	err = s.SetMessagePayloadWithEncoding(message, enc)
+	return
+}
+
+// NewBinaryMessagePayloadWithEncoding returns new binary ShortMessage carried by message_payload TLV.
+func NewBinaryMessagePayloadWithEncoding(messageData []byte, enc data.Encoding) (s ShortMessage, err error) {
+	err = s.SetMessagePayloadDataWithEncoding(messageData, enc)
+	return
+}
+
 // NewLongMessage returns long message splitted into multiple short message
 func NewLongMessage(message string) (s []*ShortMessage, err error) {
 	return NewLongMessageWithEncoding(message, data.GSM7BIT)
@@ -81,6 +122,35 @@ func (c *ShortMessage) SetMessageWithEncoding(message string, enc data.Encoding)
 	return
 }
 
+// SetMessagePayloadWithEncoding sets message with encoding, to be carried by message_payload TLV.
+// sm_length is marshalled as zero.
+func (c *ShortMessage) SetMessagePayloadWithEncoding(message string, enc data.Encoding) (err error) {
+	var d []byte
+	if d, err = enc.Encode(message); err == nil {
+		if err = c.SetMessagePayloadDataWithEncoding(d, enc); err == nil {
+			c.message = message
+		}
+	}
+	return
+}
+
+// SetMessagePayloadDataWithEncoding sets underlying raw data, to be carried by message_payload TLV.
+func (c *ShortMessage) SetMessagePayloadDataWithEncoding(d []byte, enc data.Encoding) (err error) {
+	if len(d)+c.udHeader.UDHL() > data.SM_MSG_PAYLOAD_LEN {
+		err = errors.ErrMessagePayloadTooLarge
+	} else {
+		c.messageData = d
+		c.enc = enc
+		c.asPayload = true
+	}
+	return
+}
+
+// IsMessagePayload returns true if message is carried by message_payload TLV.
+func (c *ShortMessage) IsMessagePayload() bool {
+	return c.asPayload
+}
+
 // SetLongMessageWithEnc sets ShortMessage with message longer than  256 bytes
 // callers are expected to call Split() after this
 func (c *ShortMessage) SetLongMessageWithEnc(message string, enc data.Encoding) (err error) {
@@ -140,6 +210,15 @@ func (c *ShortMessage) GetMessageWithEncoding(enc data.Encoding) (st string, err
 // NOTE: split() will return array of length 1 if data length is still within the limit
 // The encoding interface can implement the data.Splitter interface for ad-hoc splitting rule
 func (c *ShortMessage) split() (multiSM []*ShortMessage, err error) {
+	multiSM, _, err = c.splitWithConcatMode(ConcatUDH8BitRef)
+	return
+}
+
+// splitWithConcatMode works as split() but with linking segments by mode.
+// Returned ref is the reference number shared by all segments.
+//
+// With ConcatSAR, segments have no UDH, the caller must set SAR TLVs on each PDU.
+func (c *ShortMessage) splitWithConcatMode(mode ConcatMode) (multiSM []*ShortMessage, ref uint16, err error) {
 	var encoding data.Encoding
 	if c.enc == nil {
 		encoding = data.GSM7BIT
@@ -156,7 +235,7 @@ func (c *ShortMessage) split() (multiSM []*ShortMessage, err error) {
 		return
 	}
 
-	// Reserve 6 bytes for concat message UDH
+	// Reserve 6 bytes for concat message UDH (7 bytes for 16-bit reference, none for SAR)
 	//
 	// Good references:
 	// - https://help.goacoustic.com/hc/en-us/articles/360043843154--How-character-encoding-affects-SMS-message-length
@@ -165,27 +244,35 @@ func (c *ShortMessage) split() (multiSM []*ShortMessage, err error) {
 	// Limitation is 160 GSM-7 characters and we also need 6 bytes for UDH
 	// -> 134 octets per segment
 	// -> this leaves 153 GSM-7 characters per segment.
-	segments, err := splitter.EncodeSplit(c.message, data.SM_GSM_MSG_LEN-6)
+	segments, err := splitter.EncodeSplit(c.message, uint(data.SM_GSM_MSG_LEN-mode.udhLength()))
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	// prealloc result
 	multiSM = make([]*ShortMessage, 0, len(segments))
 
 	// all segments will have the same ref id
-	ref := getRefNum()
+	ref = uint16(getRefNum())
 
 	// construct SM(s)
 	for i, seg := range segments {
 		// create new SM, encode data
-		multiSM = append(multiSM, &ShortMessage{
+		sm := &ShortMessage{
 			enc: c.enc,
 			// message: we don't really care
 			messageData:       seg,
 			withoutDataCoding: c.withoutDataCoding,
-			udHeader:          UDH{NewIEConcatMessage(uint8(len(segments)), uint8(i+1), uint8(ref))},
-		})
+		}
+
+		switch mode {
+		case ConcatUDH16BitRef:
+			sm.udHeader = UDH{NewIEConcatMessage16(uint8(len(segments)), uint8(i+1), ref)}
+		case ConcatUDH8BitRef:
+			sm.udHeader = UDH{NewIEConcatMessage(uint8(len(segments)), uint8(i+1), uint8(ref))}
+		}
+
+		multiSM = append(multiSM, sm)
 	}
 
 	return
@@ -198,6 +285,11 @@ func (c *ShortMessage) Marshal(b *ByteBuffer) {
 		n      = byte(len(c.messageData))
 	)
 
+	// message data goes to message_payload TLV, see marshalPayload
+	if c.asPayload {
+		n = 0
+	}
+
 	// Prepend UDH to message data if there are any
 	if c.udHeader != nil && c.udHeader.UDHL() > 0 {
 		udhBin, _ = c.udHeader.MarshalBinary()
@@ -221,7 +313,7 @@ func (c *ShortMessage) Marshal(b *ByteBuffer) {
 	_ = b.WriteByte(c.SmDefaultMsgID)
 
 	// sm_length
-	if udhBin != nil {
+	if udhBin != nil && !c.asPayload {
 		_ = b.WriteByte(byte(int(n) + len(udhBin)))
 		b.Write(udhBin)
 	} else {
@@ -278,6 +370,50 @@ func (c *ShortMessage) Unmarshal(b *ByteBuffer, udhi bool) (err error) {
 	return
 }
 
+// marshalPayload puts UDH and message data into message_payload TLV when message is carried as payload.
+func (c *ShortMessage) marshalPayload(b *base) {
+	if !c.asPayload {
+		return
+	}
+
+	payload := c.messageData
+	if c.udHeader != nil && c.udHeader.UDHL() > 0 {
+		udhBin, _ := c.udHeader.MarshalBinary()
+		payload = append(udhBin, c.messageData...)
+	}
+	b.RegisterOptionalParam(Field{Tag: TagMessagePayload, Data: payload})
+}
+
+// unmarshalPayload takes message from message_payload TLV when short_message is empty.
+// UDH inside the payload is extracted the same way as for short_message.
+func (c *ShortMessage) unmarshalPayload(b *base, udhi bool) (err error) {
+	field, ok := b.OptionalParameters[TagMessagePayload]
+	if !ok || len(c.messageData) > 0 || len(field.Data) == 0 {
+		return
+	}
+
+	c.messageData = field.Data
+	c.asPayload = true
+
+	if udhi {
+		udh := UDH{}
+		if _, err = udh.UnmarshalBinary(c.messageData); err != nil {
+			return
+		}
+
+		c.udHeader = udh
+
+		f := c.udHeader.UDHL()
+		if f > len(c.messageData) {
+			err = errors.ErrUDHTooLong
+			return
+		}
+
+		c.messageData = c.messageData[f:]
+	}
+	return
+}
+
 // Encoding returns message encoding.
 func (c *ShortMessage) Encoding() data.Encoding {
 	return c.enc
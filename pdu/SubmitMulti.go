@@ -53,8 +53,50 @@ func (c *SubmitMulti) GetResponse() PDU {
 	return NewSubmitMultiRespFromReq(c)
 }
 
+// Split split a single long text message into multiple SubmitMulti PDU,
+// the same way SubmitSM.Split does.
+func (c *SubmitMulti) Split() (multiSubSM []*SubmitMulti, err error) {
+	return c.SplitWithConcatMode(ConcatUDH8BitRef)
+}
+
+// SplitWithConcatMode works as Split but links segments according to mode:
+// UDH with 8-bit or 16-bit reference number, or SAR TLVs.
+func (c *SubmitMulti) SplitWithConcatMode(mode ConcatMode) (multiSubSM []*SubmitMulti, err error) {
+	multiSubSM = []*SubmitMulti{}
+
+	multiMsg, ref, err := c.Message.splitWithConcatMode(mode)
+	if err != nil {
+		return
+	}
+
+	esmClass := c.EsmClass // no need to "or" with SM_UDH_GSM when a message has a single part
+	if len(multiMsg) > 1 && mode != ConcatSAR {
+		esmClass = c.EsmClass | data.SM_UDH_GSM // must set to indicate UDH
+	}
+
+	for i, msg := range multiMsg {
+		multiSubSM = append(multiSubSM, &SubmitMulti{
+			base:                 c.base.segment(mode, ref, len(multiMsg), i+1),
+			ServiceType:          c.ServiceType,
+			SourceAddr:           c.SourceAddr,
+			DestAddrs:            c.DestAddrs,
+			EsmClass:             esmClass,
+			ProtocolID:           c.ProtocolID,
+			PriorityFlag:         c.PriorityFlag,
+			ScheduleDeliveryTime: c.ScheduleDeliveryTime,
+			ValidityPeriod:       c.ValidityPeriod,
+			RegisteredDelivery:   c.RegisteredDelivery,
+			ReplaceIfPresentFlag: c.ReplaceIfPresentFlag,
+			Message:              *msg,
+		})
+	}
+	return
+}
+
 // Marshal implements PDU interface.
 func (c *SubmitMulti) Marshal(b *ByteBuffer) {
+	c.Message.marshalPayload(&c.base)
+
 	c.base.marshal(b, func(b *ByteBuffer) {
 		b.Grow(len(c.ServiceType) + len(c.ScheduleDeliveryTime) + len(c.ValidityPeriod) + 10)
 
@@ -73,8 +115,8 @@ func (c *SubmitMulti) Marshal(b *ByteBuffer) {
 }
 
 // Unmarshal implements PDU interface.
-func (c *SubmitMulti) Unmarshal(b *ByteBuffer) error {
-	return c.base.unmarshal(b, func(b *ByteBuffer) (err error) {
+func (c *SubmitMulti) Unmarshal(b *ByteBuffer) (err error) {
+	err = c.base.unmarshal(b, func(b *ByteBuffer) (err error) {
 		if c.ServiceType, err = b.ReadCString(); err == nil {
 			if err = c.SourceAddr.Unmarshal(b); err == nil {
 				if err = c.DestAddrs.Unmarshal(b); err == nil {
@@ -98,4 +140,11 @@ func (c *SubmitMulti) Unmarshal(b *ByteBuffer) error {
 		}
 		return
 	})
+	// payload is decoded even if another optional parameter is malformed
+	if _, invalidTLV := err.(*InvalidTLVError); err == nil || invalidTLV {
+		if payloadErr := c.Message.unmarshalPayload(&c.base, (c.EsmClass&data.SM_UDH_GSM) > 0); payloadErr != nil {
+			err = payloadErr
+		}
+	}
+	return
 }
@@ -108,3 +108,25 @@ func TestSubmitMultiwithUDH(t *testing.T) {
 		data.SUBMIT_MULTI,
 	)
 }
+
+func TestSubmitMultiSplit(t *testing.T) {
+	v := NewSubmitMulti().(*SubmitMulti)
+	require.NoError(t, v.Message.SetLongMessageWithEnc("biggest gift của Christmas là có nhiều big/challenging/meaningful problems để sấp mặt làm", data.UCS2))
+
+	multi, err := v.SplitWithConcatMode(ConcatUDH16BitRef)
+	require.NoError(t, err)
+	require.Len(t, multi, 2)
+	for i, sm := range multi {
+		require.NotZero(t, sm.EsmClass&data.SM_UDH_GSM)
+		totalParts, partNum, _, found := sm.Message.UDH().GetConcatInfo16()
+		require.True(t, found)
+		require.Equal(t, byte(2), totalParts)
+		require.Equal(t, byte(i+1), partNum)
+	}
+
+	multi, err = v.Split()
+	require.NoError(t, err)
+	require.Len(t, multi, 2)
+	_, _, _, found := multi[0].Message.UDH().GetConcatInfo()
+	require.True(t, found)
+}
@@ -64,21 +64,27 @@ func (c *SubmitSM) GetResponse() PDU {
 // If the message is short enough and doesn't need splitting,
 // Split() returns an array of length 1
 func (c *SubmitSM) Split() (multiSubSM []*SubmitSM, err error) {
+	return c.SplitWithConcatMode(ConcatUDH8BitRef)
+}
+
+// SplitWithConcatMode works as Split but links segments according to mode:
+// UDH with 8-bit or 16-bit reference number, or SAR TLVs.
+func (c *SubmitSM) SplitWithConcatMode(mode ConcatMode) (multiSubSM []*SubmitSM, err error) {
 	multiSubSM = []*SubmitSM{}
 
-	multiMsg, err := c.Message.split()
+	multiMsg, ref, err := c.Message.splitWithConcatMode(mode)
 	if err != nil {
 		return
 	}
 
 	esmClass := c.EsmClass // no need to "or" with SM_UDH_GSM when a message has a single part
-	if len(multiMsg) > 1 {
+	if len(multiMsg) > 1 && mode != ConcatSAR {
 		esmClass = c.EsmClass | data.SM_UDH_GSM // must set to indicate UDH
 	}
 
-	for _, msg := range multiMsg {
+	for i, msg := range multiMsg {
 		multiSubSM = append(multiSubSM, &SubmitSM{
-			base:                 c.base,
+			base:                 c.base.segment(mode, ref, len(multiMsg), i+1),
 			ServiceType:          c.ServiceType,
 			SourceAddr:           c.SourceAddr,
 			DestAddr:             c.DestAddr,
@@ -97,6 +103,8 @@ func (c *SubmitSM) Split() (multiSubSM []*SubmitSM, err error) {
 
 // Marshal implements PDU interface.
 func (c *SubmitSM) Marshal(b *ByteBuffer) {
+	c.Message.marshalPayload(&c.base)
+
 	c.base.marshal(b, func(b *ByteBuffer) {
 		b.Grow(len(c.ServiceType) + len(c.ScheduleDeliveryTime) + len(c.ValidityPeriod) + 10)
 
@@ -115,8 +123,8 @@ func (c *SubmitSM) Marshal(b *ByteBuffer) {
 }
 
 // Unmarshal implements PDU interface.
-func (c *SubmitSM) Unmarshal(b *ByteBuffer) error {
-	return c.base.unmarshal(b, func(b *ByteBuffer) (err error) {
+func (c *SubmitSM) Unmarshal(b *ByteBuffer) (err error) {
+	err = c.base.unmarshal(b, func(b *ByteBuffer) (err error) {
 		if c.ServiceType, err = b.ReadCString(); err == nil {
 			if err = c.SourceAddr.Unmarshal(b); err == nil {
 				if err = c.DestAddr.Unmarshal(b); err == nil {
@@ -140,4 +148,11 @@ func (c *SubmitSM) Unmarshal(b *ByteBuffer) error {
 		}
 		return
 	})
+	// payload is decoded even if another optional parameter is malformed
+	if _, invalidTLV := err.(*InvalidTLVError); err == nil || invalidTLV {
+		if payloadErr := c.Message.unmarshalPayload(&c.base, (c.EsmClass&data.SM_UDH_GSM) > 0); payloadErr != nil {
+			err = payloadErr
+		}
+	}
+	return
 }
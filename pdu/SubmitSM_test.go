@@ -1,9 +1,11 @@
 package pdu
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/errors"
 
 	"github.com/stretchr/testify/require"
 )
@@ -41,3 +43,111 @@ func TestSubmitSM(t *testing.T) {
 		data.SUBMIT_SM,
 	)
 }
+
+func TestSubmitSMSplit(t *testing.T) {
+	long := "abcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyz1234"
+
+	newSubmitSM := func() *SubmitSM {
+		v := NewSubmitSM().(*SubmitSM)
+		require.NoError(t, v.Message.SetLongMessageWithEnc(long, data.GSM7BIT))
+		v.RegisterOptionalParam(Field{Tag: TagUserMessageReference, Data: []byte{0x00, 0x01}})
+		return v
+	}
+
+	t.Run("udh8bit", func(t *testing.T) {
+		multi, err := newSubmitSM().Split()
+		require.NoError(t, err)
+		require.Len(t, multi, 2)
+
+		for i, v := range multi {
+			require.NotZero(t, v.EsmClass&data.SM_UDH_GSM)
+			totalParts, partNum, _, found := v.Message.UDH().GetConcatInfo()
+			require.True(t, found)
+			require.Equal(t, byte(2), totalParts)
+			require.Equal(t, byte(i+1), partNum)
+		}
+	})
+
+	t.Run("udh16bit", func(t *testing.T) {
+		multi, err := newSubmitSM().SplitWithConcatMode(ConcatUDH16BitRef)
+		require.NoError(t, err)
+		require.Len(t, multi, 2)
+
+		_, _, ref, _ := multi[0].Message.UDH().GetConcatInfo16()
+		for i, v := range multi {
+			require.NotZero(t, v.EsmClass&data.SM_UDH_GSM)
+			require.Equal(t, data.UDH_CONCAT_MSG_16_BIT_REF, v.Message.UDH()[0].ID)
+
+			totalParts, partNum, mref, found := v.Message.UDH().GetConcatInfo16()
+			require.True(t, found)
+			require.Equal(t, byte(2), totalParts)
+			require.Equal(t, byte(i+1), partNum)
+			require.Equal(t, ref, mref)
+
+			buf := NewBuffer(nil)
+			v.Message.Marshal(buf)
+			require.LessOrEqual(t, buf.Len()-3, data.SM_GSM_MSG_LEN)
+		}
+	})
+
+	t.Run("sar", func(t *testing.T) {
+		multi, err := newSubmitSM().SplitWithConcatMode(ConcatSAR)
+		require.NoError(t, err)
+		require.Len(t, multi, 2)
+
+		ref := multi[0].OptionalParameters[TagSarMsgRefNum].Data
+		for i, v := range multi {
+			require.Zero(t, v.EsmClass&data.SM_UDH_GSM)
+			require.Nil(t, v.Message.UDH())
+			require.Equal(t, ref, v.OptionalParameters[TagSarMsgRefNum].Data)
+			require.Equal(t, []byte{2}, v.OptionalParameters[TagSarTotalSegments].Data)
+			require.Equal(t, []byte{byte(i + 1)}, v.OptionalParameters[TagSarSegmentSeqnum].Data)
+			require.Equal(t, []byte{0x00, 0x01}, v.OptionalParameters[TagUserMessageReference].Data)
+		}
+
+		// segments must not share optional parameters
+		buf := NewBuffer(nil)
+		multi[0].Marshal(buf)
+		p, err := Parse(buf)
+		require.NoError(t, err)
+		require.Equal(t, []byte{1}, p.(*SubmitSM).OptionalParameters[TagSarSegmentSeqnum].Data)
+	})
+
+	t.Run("sarSinglePart", func(t *testing.T) {
+		v := NewSubmitSM().(*SubmitSM)
+		require.NoError(t, v.Message.SetLongMessageWithEnc("short", data.GSM7BIT))
+
+		multi, err := v.SplitWithConcatMode(ConcatSAR)
+		require.NoError(t, err)
+		require.Len(t, multi, 1)
+		require.Empty(t, multi[0].OptionalParameters)
+	})
+}
+
+func TestSubmitSMMessagePayload(t *testing.T) {
+	long := strings.Repeat("0123456789", 4000)
+
+	v := NewSubmitSM().(*SubmitSM)
+	v.SequenceNumber = 13
+	message, err := NewMessagePayloadWithEncoding(long, data.GSM7BIT)
+	require.NoError(t, err)
+	require.True(t, message.IsMessagePayload())
+	v.Message = message
+
+	buf := NewBuffer(nil)
+	v.Marshal(buf)
+
+	p, err := Parse(buf)
+	require.NoError(t, err)
+
+	sm := p.(*SubmitSM)
+	require.True(t, sm.Message.IsMessagePayload())
+	require.Len(t, sm.OptionalParameters[TagMessagePayload].Data, len(long))
+
+	m, err := sm.Message.GetMessage()
+	require.NoError(t, err)
+	require.Equal(t, long, m)
+
+	_, err = NewMessagePayloadWithEncoding(strings.Repeat("a", data.SM_MSG_PAYLOAD_LEN+1), data.GSM7BIT)
+	require.Equal(t, errors.ErrMessagePayloadTooLarge, err)
+}
@@ -18,49 +18,70 @@ func (t Tag) Hex() string {
 
 // Common Tag-Length-Value (TLV) tags.
 const (
-	TagDestAddrSubunit          Tag = 0x0005
-	TagDestNetworkType          Tag = 0x0006
-	TagDestBearerType           Tag = 0x0007
-	TagDestTelematicsID         Tag = 0x0008
-	TagSourceAddrSubunit        Tag = 0x000D
-	TagSourceNetworkType        Tag = 0x000E
-	TagSourceBearerType         Tag = 0x000F
-	TagSourceTelematicsID       Tag = 0x0010
-	TagQosTimeToLive            Tag = 0x0017
-	TagPayloadType              Tag = 0x0019
-	TagAdditionalStatusInfoText Tag = 0x001D
-	TagReceiptedMessageID       Tag = 0x001E
-	TagMsMsgWaitFacilities      Tag = 0x0030
-	TagPrivacyIndicator         Tag = 0x0201
-	TagSourceSubaddress         Tag = 0x0202
-	TagDestSubaddress           Tag = 0x0203
-	TagUserMessageReference     Tag = 0x0204
-	TagUserResponseCode         Tag = 0x0205
-	TagSourcePort               Tag = 0x020A
-	TagDestinationPort          Tag = 0x020B
-	TagSarMsgRefNum             Tag = 0x020C
-	TagLanguageIndicator        Tag = 0x020D
-	TagSarTotalSegments         Tag = 0x020E
-	TagSarSegmentSeqnum         Tag = 0x020F
-	TagCallbackNumPresInd       Tag = 0x0302
-	TagCallbackNumAtag          Tag = 0x0303
-	TagNumberOfMessages         Tag = 0x0304
-	TagCallbackNum              Tag = 0x0381
-	TagDpfResult                Tag = 0x0420
-	TagSetDpf                   Tag = 0x0421
-	TagMsAvailabilityStatus     Tag = 0x0422
-	TagNetworkErrorCode         Tag = 0x0423
-	TagMessagePayload           Tag = 0x0424
-	TagDeliveryFailureReason    Tag = 0x0425
-	TagMoreMessagesToSend       Tag = 0x0426
-	TagMessageStateOption       Tag = 0x0427
-	TagUssdServiceOp            Tag = 0x0501
-	TagDisplayTime              Tag = 0x1201
-	TagSmsSignal                Tag = 0x1203
-	TagMsValidity               Tag = 0x1204
-	TagAlertOnMessageDelivery   Tag = 0x130C
-	TagItsReplyType             Tag = 0x1380
-	TagItsSessionInfo           Tag = 0x1383
+	TagDestAddrSubunit            Tag = 0x0005
+	TagDestNetworkType            Tag = 0x0006
+	TagDestBearerType             Tag = 0x0007
+	TagDestTelematicsID           Tag = 0x0008
+	TagSourceAddrSubunit          Tag = 0x000D
+	TagSourceNetworkType          Tag = 0x000E
+	TagSourceBearerType           Tag = 0x000F
+	TagSourceTelematicsID         Tag = 0x0010
+	TagQosTimeToLive              Tag = 0x0017
+	TagPayloadType                Tag = 0x0019
+	TagAdditionalStatusInfoText   Tag = 0x001D
+	TagReceiptedMessageID         Tag = 0x001E
+	TagMsMsgWaitFacilities        Tag = 0x0030
+	TagPrivacyIndicator           Tag = 0x0201
+	TagSourceSubaddress           Tag = 0x0202
+	TagDestSubaddress             Tag = 0x0203
+	TagUserMessageReference       Tag = 0x0204
+	TagUserResponseCode           Tag = 0x0205
+	TagSourcePort                 Tag = 0x020A
+	TagDestinationPort            Tag = 0x020B
+	TagSarMsgRefNum               Tag = 0x020C
+	TagLanguageIndicator          Tag = 0x020D
+	TagSarTotalSegments           Tag = 0x020E
+	TagSarSegmentSeqnum           Tag = 0x020F
+	TagScInterfaceVersion         Tag = 0x0210
+	TagCallbackNumPresInd         Tag = 0x0302
+	TagCallbackNumAtag            Tag = 0x0303
+	TagNumberOfMessages           Tag = 0x0304
+	TagCallbackNum                Tag = 0x0381
+	TagDpfResult                  Tag = 0x0420
+	TagSetDpf                     Tag = 0x0421
+	TagMsAvailabilityStatus       Tag = 0x0422
+	TagNetworkErrorCode           Tag = 0x0423
+	TagMessagePayload             Tag = 0x0424
+	TagDeliveryFailureReason      Tag = 0x0425
+	TagMoreMessagesToSend         Tag = 0x0426
+	TagMessageStateOption         Tag = 0x0427
+	TagCongestionState            Tag = 0x0428
+	TagUssdServiceOp              Tag = 0x0501
+	TagBroadcastChannelIndicator  Tag = 0x0600
+	TagBroadcastContentType       Tag = 0x0601
+	TagBroadcastContentTypeInfo   Tag = 0x0602
+	TagBroadcastMessageClass      Tag = 0x0603
+	TagBroadcastRepNum            Tag = 0x0604
+	TagBroadcastFrequencyInterval Tag = 0x0605
+	TagBroadcastAreaIdentifier    Tag = 0x0606
+	TagBroadcastErrorStatus       Tag = 0x0607
+	TagBroadcastAreaSuccess       Tag = 0x0608
+	TagBroadcastEndTime           Tag = 0x0609
+	TagBroadcastServiceGroup      Tag = 0x060A
+	TagBillingIdentification      Tag = 0x060B
+	TagSourceNetworkID            Tag = 0x060D
+	TagDestNetworkID              Tag = 0x060E
+	TagSourceNodeID               Tag = 0x060F
+	TagDestNodeID                 Tag = 0x0610
+	TagDestAddrNpResolution       Tag = 0x0611
+	TagDestAddrNpInformation      Tag = 0x0612
+	TagDestAddrNpCountry          Tag = 0x0613
+	TagDisplayTime                Tag = 0x1201
+	TagSmsSignal                  Tag = 0x1203
+	TagMsValidity                 Tag = 0x1204
+	TagAlertOnMessageDelivery     Tag = 0x130C
+	TagItsReplyType               Tag = 0x1380
+	TagItsSessionInfo             Tag = 0x1383
 )
 
 // Field is a PDU Tag-Length-Value (TLV) field
@@ -94,7 +115,7 @@ func (t *Field) Unmarshal(b *ByteBuffer) (err error) {
 	if tag, err = b.ReadShort(); err == nil {
 		t.Tag = Tag(tag)
 		if ln, err = b.ReadShort(); err == nil {
-			t.Data, err = b.ReadN(int(ln))
+			t.Data, err = b.ReadN(int(uint16(ln)))
 		}
 	}
 	return
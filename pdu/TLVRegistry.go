@@ -0,0 +1,244 @@
+package pdu
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/errors"
+)
+
+// TLVType is the value type of an optional parameter.
+type TLVType byte
+
+const (
+	// TLVOctets is an octet string, e.g. network_error_code or callback_num.
+	TLVOctets TLVType = iota
+
+	// TLVUint8 is 1-octet integer, also used for bitfields like ms_msg_wait_facilities.
+	TLVUint8
+
+	// TLVUint16 is 2-octet integer.
+	TLVUint16
+
+	// TLVUint32 is 4-octet integer.
+	TLVUint32
+
+	// TLVCString is a C-Octet string, NULL terminated.
+	TLVCString
+)
+
+// TLVSpec describes value type and length limits of an optional parameter.
+type TLVSpec struct {
+	Type   TLVType
+	MinLen int
+	MaxLen int
+}
+
+func fixed(t TLVType, size int) TLVSpec {
+	return TLVSpec{Type: t, MinLen: size, MaxLen: size}
+}
+
+var tlvRegistry = map[Tag]TLVSpec{
+	TagDestAddrSubunit:          fixed(TLVUint8, 1),
+	TagDestNetworkType:          fixed(TLVUint8, 1),
+	TagDestBearerType:           fixed(TLVUint8, 1),
+	TagDestTelematicsID:         fixed(TLVUint16, 2),
+	TagSourceAddrSubunit:        fixed(TLVUint8, 1),
+	TagSourceNetworkType:        fixed(TLVUint8, 1),
+	TagSourceBearerType:         fixed(TLVUint8, 1),
+	TagSourceTelematicsID:       fixed(TLVUint8, 1),
+	TagQosTimeToLive:            {Type: TLVUint32, MinLen: data.OPT_PAR_QOS_TIME_TO_LIVE_MIN, MaxLen: data.OPT_PAR_QOS_TIME_TO_LIVE_MAX},
+	TagPayloadType:              fixed(TLVUint8, 1),
+	TagAdditionalStatusInfoText: {Type: TLVCString, MinLen: data.OPT_PAR_ADD_STAT_INFO_MIN, MaxLen: data.OPT_PAR_ADD_STAT_INFO_MAX},
+	TagReceiptedMessageID:       {Type: TLVCString, MinLen: data.OPT_PAR_RECP_MSG_ID_MIN, MaxLen: data.OPT_PAR_RECP_MSG_ID_MAX},
+	TagMsMsgWaitFacilities:      fixed(TLVUint8, 1),
+	TagPrivacyIndicator:         fixed(TLVUint8, 1),
+	TagSourceSubaddress:         {Type: TLVOctets, MinLen: data.OPT_PAR_SRC_SUBADDR_MIN, MaxLen: data.OPT_PAR_SRC_SUBADDR_MAX},
+	TagDestSubaddress:           {Type: TLVOctets, MinLen: data.OPT_PAR_DEST_SUBADDR_MIN, MaxLen: data.OPT_PAR_DEST_SUBADDR_MAX},
+	TagUserMessageReference:     fixed(TLVUint16, 2),
+	TagUserResponseCode:         fixed(TLVUint8, 1),
+	TagSourcePort:               fixed(TLVUint16, 2),
+	TagDestinationPort:          fixed(TLVUint16, 2),
+	TagSarMsgRefNum:             fixed(TLVUint16, 2),
+	TagLanguageIndicator:        fixed(TLVUint8, 1),
+	TagSarTotalSegments:         fixed(TLVUint8, 1),
+	TagSarSegmentSeqnum:         fixed(TLVUint8, 1),
+	TagScInterfaceVersion:       fixed(TLVUint8, 1),
+	TagCallbackNumPresInd:       fixed(TLVUint8, 1),
+	TagCallbackNumAtag:          {Type: TLVOctets, MinLen: data.OPT_PAR_CALLBACK_NUM_ATAG_MIN, MaxLen: data.OPT_PAR_CALLBACK_NUM_ATAG_MAX},
+	TagNumberOfMessages:         fixed(TLVUint8, 1),
+	TagCallbackNum:              {Type: TLVOctets, MinLen: data.OPT_PAR_CALLBACK_NUM_MIN, MaxLen: data.OPT_PAR_CALLBACK_NUM_MAX},
+	TagDpfResult:                fixed(TLVUint8, 1),
+	TagSetDpf:                   fixed(TLVUint8, 1),
+	TagMsAvailabilityStatus:     fixed(TLVUint8, 1),
+	TagNetworkErrorCode:         {Type: TLVOctets, MinLen: data.OPT_PAR_NW_ERR_CODE_MIN, MaxLen: data.OPT_PAR_NW_ERR_CODE_MAX},
+	// OPT_PAR_MSG_PAYLOAD_MAX is not enforced, SMSCs commonly accept payload up to the TLV length limit.
+	TagMessagePayload:        {Type: TLVOctets, MinLen: data.OPT_PAR_MSG_PAYLOAD_MIN, MaxLen: data.SM_MSG_PAYLOAD_LEN},
+	TagDeliveryFailureReason: fixed(TLVUint8, 1),
+	TagMoreMessagesToSend:    fixed(TLVUint8, 1),
+	TagMessageStateOption:    fixed(TLVUint8, 1),
+	TagCongestionState:       fixed(TLVUint8, 1),
+	TagUssdServiceOp:         fixed(TLVUint8, 1),
+	// SMPP 5.0 broadcast and network parameters
+	TagBroadcastChannelIndicator:  fixed(TLVUint8, 1),
+	TagBroadcastContentType:       fixed(TLVOctets, 3),
+	TagBroadcastContentTypeInfo:   {Type: TLVOctets, MinLen: 0, MaxLen: data.OPT_PAR_BCAST_CONTENT_TYPE_INFO_MAX},
+	TagBroadcastMessageClass:      fixed(TLVUint8, 1),
+	TagBroadcastRepNum:            fixed(TLVUint16, 2),
+	TagBroadcastFrequencyInterval: fixed(TLVOctets, 3),
+	TagBroadcastAreaIdentifier:    {Type: TLVOctets, MinLen: data.OPT_PAR_BCAST_AREA_ID_MIN, MaxLen: data.OPT_PAR_BCAST_AREA_ID_MAX},
+	TagBroadcastErrorStatus:       fixed(TLVUint32, 4),
+	TagBroadcastAreaSuccess:       fixed(TLVUint8, 1),
+	TagBroadcastEndTime:           {Type: TLVCString, MinLen: data.OPT_PAR_BCAST_END_TIME_MIN, MaxLen: data.OPT_PAR_BCAST_END_TIME_MAX},
+	TagBroadcastServiceGroup:      {Type: TLVOctets, MinLen: 0, MaxLen: data.OPT_PAR_BCAST_SRV_GROUP_MAX},
+	TagBillingIdentification:      {Type: TLVOctets, MinLen: 0, MaxLen: data.OPT_PAR_BILLING_ID_MAX},
+	TagSourceNetworkID:            {Type: TLVCString, MinLen: data.OPT_PAR_SRC_NW_ID_MIN, MaxLen: data.OPT_PAR_SRC_NW_ID_MAX},
+	TagDestNetworkID:              {Type: TLVCString, MinLen: data.OPT_PAR_DST_NW_ID_MIN, MaxLen: data.OPT_PAR_DST_NW_ID_MAX},
+	TagSourceNodeID:               fixed(TLVOctets, 6),
+	TagDestNodeID:                 fixed(TLVOctets, 6),
+	TagDestAddrNpResolution:       fixed(TLVUint8, 1),
+	TagDestAddrNpInformation:      fixed(TLVOctets, 10),
+	TagDestAddrNpCountry:          {Type: TLVOctets, MinLen: data.OPT_PAR_DST_ADDR_NP_COUNTRY_MIN, MaxLen: data.OPT_PAR_DST_ADDR_NP_COUNTRY_MAX},
+	TagDisplayTime:                fixed(TLVUint8, 1),
+	TagSmsSignal:                  fixed(TLVUint16, 2),
+	TagMsValidity:                 fixed(TLVUint8, 1),
+	TagAlertOnMessageDelivery:     {Type: TLVOctets, MinLen: 0, MaxLen: 1},
+	TagItsReplyType:               fixed(TLVUint8, 1),
+	TagItsSessionInfo:             fixed(TLVOctets, 2),
+}
+
+// LookupTLV returns spec of registered optional parameter.
+func LookupTLV(tag Tag) (spec TLVSpec, found bool) {
+	spec, found = tlvRegistry[tag]
+	return
+}
+
+// RegisterTLV registers spec for an optional parameter, e.g. vendor specific tag,
+// so that its values are validated during Unmarshal.
+//
+// RegisterTLV is not safe for concurrent use and should be called during initialization.
+func RegisterTLV(tag Tag, spec TLVSpec) {
+	tlvRegistry[tag] = spec
+}
+
+// Validate checks data against type and length limits.
+//
+// C-Octet strings without NULL terminator are tolerated, since many SMSCs omit it.
+func (s TLVSpec) Validate(data []byte) bool {
+	if len(data) < s.MinLen || len(data) > s.MaxLen {
+		return false
+	}
+	switch s.Type {
+	case TLVUint8:
+		return len(data) <= 1
+	case TLVUint16:
+		return len(data) <= 2
+	case TLVUint32:
+		return len(data) <= 4
+	case TLVCString:
+		for i := 0; i < len(data)-1; i++ {
+			if data[i] == 0x00 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// InvalidTLVError is returned by Parse when an optional parameter violates its registered spec.
+//
+// The PDU is read completely and returned along with the error, without the malformed parameter,
+// so the stream stays aligned and the PDU can be answered, e.g. with ESME_RINVOPTPARAMVAL.
+type InvalidTLVError struct {
+	Field Field
+}
+
+func (e *InvalidTLVError) Error() string {
+	return fmt.Sprintf("%s: tag %s, length %d", errors.ErrInvalidTLV, e.Field.Tag.Hex(), len(e.Field.Data))
+}
+
+// Unwrap returns errors.ErrInvalidTLV.
+func (e *InvalidTLVError) Unwrap() error {
+	return errors.ErrInvalidTLV
+}
+
+func (t *Field) validate() error {
+	if spec, found := tlvRegistry[t.Tag]; found && !spec.Validate(t.Data) {
+		return &InvalidTLVError{Field: *t}
+	}
+	return nil
+}
+
+// NewUint8Field returns field with 1-octet integer value.
+func NewUint8Field(tag Tag, v uint8) Field {
+	return Field{Tag: tag, Data: []byte{v}}
+}
+
+// NewUint16Field returns field with 2-octet integer value.
+func NewUint16Field(tag Tag, v uint16) Field {
+	f := Field{Tag: tag, Data: make([]byte, 2)}
+	binary.BigEndian.PutUint16(f.Data, v)
+	return f
+}
+
+// NewUint32Field returns field with 4-octet integer value.
+func NewUint32Field(tag Tag, v uint32) Field {
+	f := Field{Tag: tag, Data: make([]byte, 4)}
+	binary.BigEndian.PutUint32(f.Data, v)
+	return f
+}
+
+// NewCStringField returns field with NULL terminated string value.
+func NewCStringField(tag Tag, v string) Field {
+	return Field{Tag: tag, Data: append([]byte(v), 0x00)}
+}
+
+// NewOctetsField returns field with octet string value.
+func NewOctetsField(tag Tag, v []byte) Field {
+	return Field{Tag: tag, Data: v}
+}
+
+// uint decodes big-endian integer of at most size octets.
+func (c *base) uint(tag Tag, size int) (v uint32, found bool) {
+	f, found := c.OptionalParameters[tag]
+	if !found || len(f.Data) == 0 || len(f.Data) > size {
+		return 0, false
+	}
+	for _, b := range f.Data {
+		v = v<<8 | uint32(b)
+	}
+	return
+}
+
+// GetUint8 returns 1-octet integer value of optional parameter.
+func (c *base) GetUint8(tag Tag) (uint8, bool) {
+	v, found := c.uint(tag, 1)
+	return uint8(v), found
+}
+
+// GetUint16 returns 2-octet integer value of optional parameter.
+func (c *base) GetUint16(tag Tag) (uint16, bool) {
+	v, found := c.uint(tag, 2)
+	return uint16(v), found
+}
+
+// GetUint32 returns integer value of optional parameter, up to 4 octets.
+func (c *base) GetUint32(tag Tag) (uint32, bool) {
+	return c.uint(tag, 4)
+}
+
+// GetCString returns C-Octet string value of optional parameter, without NULL terminator.
+func (c *base) GetCString(tag Tag) (string, bool) {
+	f, found := c.OptionalParameters[tag]
+	if !found {
+		return "", false
+	}
+	return f.String(), true
+}
+
+// GetOctets returns raw value of optional parameter.
+func (c *base) GetOctets(tag Tag) ([]byte, bool) {
+	f, found := c.OptionalParameters[tag]
+	return f.Data, found
+}
@@ -0,0 +1,145 @@
+package pdu
+
+import (
+	stdErrors "errors"
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/errors"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLVAccessors(t *testing.T) {
+	v := NewSubmitSM().(*SubmitSM)
+	v.RegisterOptionalParam(NewUint8Field(TagMsMsgWaitFacilities, 0x81))
+	v.RegisterOptionalParam(NewUint16Field(TagSourcePort, 2948))
+	v.RegisterOptionalParam(NewUint32Field(TagQosTimeToLive, 86400))
+	v.RegisterOptionalParam(NewCStringField(TagReceiptedMessageID, "abc"))
+	v.RegisterOptionalParam(NewOctetsField(TagNetworkErrorCode, []byte{0x03, 0x00, 0x01}))
+
+	buf := NewBuffer(nil)
+	v.Marshal(buf)
+	p, err := Parse(buf)
+	require.NoError(t, err)
+	sm := p.(*SubmitSM)
+
+	u8, ok := sm.GetUint8(TagMsMsgWaitFacilities)
+	require.True(t, ok)
+	require.Equal(t, uint8(0x81), u8)
+
+	u16, ok := sm.GetUint16(TagSourcePort)
+	require.True(t, ok)
+	require.Equal(t, uint16(2948), u16)
+
+	u32, ok := sm.GetUint32(TagQosTimeToLive)
+	require.True(t, ok)
+	require.Equal(t, uint32(86400), u32)
+
+	st, ok := sm.GetCString(TagReceiptedMessageID)
+	require.True(t, ok)
+	require.Equal(t, "abc", st)
+
+	octets, ok := sm.GetOctets(TagNetworkErrorCode)
+	require.True(t, ok)
+	require.Equal(t, []byte{0x03, 0x00, 0x01}, octets)
+
+	_, ok = sm.GetUint16(TagDestinationPort)
+	require.False(t, ok)
+
+	// wrong accessor for the stored width
+	_, ok = sm.GetUint8(TagSourcePort)
+	require.False(t, ok)
+}
+
+func TestTLVValidation(t *testing.T) {
+	parse := func(f Field) error {
+		v := NewDeliverSM().(*DeliverSM)
+		v.RegisterOptionalParam(f)
+		buf := NewBuffer(nil)
+		v.Marshal(buf)
+		_, err := Parse(buf)
+		return err
+	}
+
+	require.NoError(t, parse(Field{Tag: TagQosTimeToLive, Data: []byte{0x10}}))
+	require.NoError(t, parse(Field{Tag: TagReceiptedMessageID, Data: []byte("no-terminator")}))
+	require.NoError(t, parse(Field{Tag: 0x1400, Data: []byte{1, 2, 3, 4, 5}}))
+
+	for _, f := range []Field{
+		{Tag: TagSourcePort, Data: []byte{0x01}},
+		{Tag: TagMessageStateOption, Data: []byte{0x01, 0x02}},
+		{Tag: TagNetworkErrorCode, Data: []byte{0x01, 0x02}},
+		{Tag: TagQosTimeToLive, Data: []byte{1, 2, 3, 4, 5}},
+		{Tag: TagReceiptedMessageID, Data: []byte("a\x00b\x00")},
+		{Tag: TagCallbackNum, Data: []byte{0x01}},
+	} {
+		err := parse(f)
+		require.True(t, stdErrors.Is(err, errors.ErrInvalidTLV), f.Tag.Hex())
+	}
+
+	RegisterTLV(0x1400, TLVSpec{Type: TLVUint8, MinLen: 1, MaxLen: 1})
+	defer delete(tlvRegistry, 0x1400)
+
+	spec, found := LookupTLV(0x1400)
+	require.True(t, found)
+	require.Equal(t, TLVUint8, spec.Type)
+	require.True(t, stdErrors.Is(parse(Field{Tag: 0x1400, Data: []byte{1, 2}}), errors.ErrInvalidTLV))
+
+	spec, found = LookupTLV(TagReceiptedMessageID)
+	require.True(t, found)
+	require.Equal(t, data.OPT_PAR_RECP_MSG_ID_MAX, spec.MaxLen)
+}
+
+func TestInvalidTLVKeepsPDU(t *testing.T) {
+	v := NewDeliverSM().(*DeliverSM)
+	v.SequenceNumber = 7
+	v.AddOptionalParam(NewUint16Field(TagUserMessageReference, 1))
+	v.AddOptionalParam(Field{Tag: TagSourcePort, Data: []byte{0x01}})
+	v.AddOptionalParam(NewUint16Field(TagDestinationPort, 2))
+
+	buf := NewBuffer(nil)
+	v.Marshal(buf)
+	_, _ = buf.Write([]byte{0xff}) // next PDU
+	p, err := Parse(buf)
+
+	var tlvErr *InvalidTLVError
+	require.True(t, stdErrors.As(err, &tlvErr))
+	require.True(t, stdErrors.Is(err, errors.ErrInvalidTLV))
+	require.Equal(t, TagSourcePort, tlvErr.Field.Tag)
+
+	// stream stays aligned, other parameters are kept
+	require.Equal(t, 1, buf.Len())
+	require.Equal(t, int32(7), p.GetSequenceNumber())
+	require.Equal(t, []Field{
+		NewUint16Field(TagUserMessageReference, 1),
+		NewUint16Field(TagDestinationPort, 2),
+	}, p.(*DeliverSM).OptionalParams())
+}
+
+func TestInvalidTLVKeepsPayload(t *testing.T) {
+	messageOf := func(p PDU) *ShortMessage {
+		switch pp := p.(type) {
+		case *SubmitSM:
+			return &pp.Message
+		case *DeliverSM:
+			return &pp.Message
+		default:
+			return &pp.(*SubmitMulti).Message
+		}
+	}
+
+	for _, v := range []PDU{NewSubmitSM(), NewDeliverSM(), NewSubmitMulti()} {
+		require.NoError(t, messageOf(v).SetMessagePayloadWithEncoding("hello", data.GSM7BIT))
+		v.RegisterOptionalParam(Field{Tag: TagSourcePort, Data: []byte{0x01}})
+
+		buf := NewBuffer(nil)
+		v.Marshal(buf)
+		p, err := Parse(buf)
+		require.True(t, stdErrors.Is(err, errors.ErrInvalidTLV))
+
+		msg, err := messageOf(p).GetMessage()
+		require.NoError(t, err)
+		require.Equal(t, "hello", msg)
+	}
+}
@@ -3,6 +3,8 @@ package pdu
 import (
 	"testing"
 
+	"github.com/linxGnu/gosmpp/data"
+
 	"github.com/stretchr/testify/require"
 )
 
@@ -27,6 +29,32 @@ func TestUserDataHeader(t *testing.T) {
 		require.Equal(t, reference, uint8(12))
 	})
 
+	t.Run("marshalBinaryUDHConcatMessage (16 bit)", func(t *testing.T) {
+		u := UDH{NewIEConcatMessage16(2, 1, 0x0c0d)}
+		b, err := u.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, "0608040c0d0201", toHex(b))
+	})
+
+	t.Run("concatInfo16", func(t *testing.T) {
+		u := UDH{NewIEConcatMessage(3, 2, 12)}
+		totalParts, sequence, reference, found := u.GetConcatInfo16()
+		require.True(t, found)
+		require.Equal(t, byte(3), totalParts)
+		require.Equal(t, byte(2), sequence)
+		require.Equal(t, uint16(12), reference)
+
+		u = UDH{{ID: data.UDH_CONCAT_MSG_16_BIT_REF, Data: []byte{0x01, 0x02, 0x03, 0x01}}}
+		totalParts, sequence, reference, found = u.GetConcatInfo16()
+		require.True(t, found)
+		require.Equal(t, byte(3), totalParts)
+		require.Equal(t, byte(1), sequence)
+		require.Equal(t, uint16(0x0102), reference)
+
+		_, _, _, found = u.GetConcatInfo()
+		require.False(t, found)
+	})
+
 	t.Run("unmarshalBinaryUDHConcatMessage", func(t *testing.T) {
 		u, rd := new(UDH), []byte{0x05, 0x00, 0x03, 0x0c, 0x02, 0x01}
 		read, err := u.UnmarshalBinary(rd)
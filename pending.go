@@ -0,0 +1,92 @@
+package gosmpp
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// GenericNackError is returned by SubmitWithContext when SMSC answers the request with generic_nack.
+type GenericNackError struct {
+	CommandStatus data.CommandStatusType
+}
+
+func (err GenericNackError) Error() string {
+	return fmt.Sprintf("generic_nack (%s): %s", err.CommandStatus, err.CommandStatus.Desc())
+}
+
+type pendingResult struct {
+	resp pdu.PDU
+	err  error
+}
+
+// pendingResponses correlates submitted requests with their responses by sequence number.
+//
+// It is kept apart from RequestStore: the store is only set up with WindowedRequestTracking
+// and may be backed by an external storage, while waiters are channels of this process.
+// Requests expired from the store are resolved here with ErrRequestExpired, responses
+// of requests found in the store are still reported to OnExpectedPduResponse.
+type pendingResponses struct {
+	mu      sync.Mutex
+	closed  bool
+	waiters map[int32]chan pendingResult
+}
+
+func newPendingResponses() *pendingResponses {
+	return &pendingResponses{
+		waiters: make(map[int32]chan pendingResult),
+	}
+}
+
+// add registers a waiter for the given sequence number.
+func (p *pendingResponses) add(sequenceNumber int32) (ch chan pendingResult, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, ErrConnectionClosing
+	}
+
+	ch = make(chan pendingResult, 1)
+	p.waiters[sequenceNumber] = ch
+	return
+}
+
+// remove forgets the waiter registered for the given sequence number.
+func (p *pendingResponses) remove(sequenceNumber int32) {
+	p.mu.Lock()
+	delete(p.waiters, sequenceNumber)
+	p.mu.Unlock()
+}
+
+// resolve hands the result to the waiter of the given sequence number, if any.
+func (p *pendingResponses) resolve(sequenceNumber int32, resp pdu.PDU, err error) (found bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ch, found := p.waiters[sequenceNumber]
+	if found {
+		delete(p.waiters, sequenceNumber)
+		ch <- pendingResult{resp: resp, err: err}
+	}
+	return
+}
+
+// close fails all waiters with err and rejects further registrations.
+func (p *pendingResponses) close(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	for seq, ch := range p.waiters {
+		delete(p.waiters, seq)
+		ch <- pendingResult{err: err}
+	}
+}
+
+// isResponse checks the response bit of the command id.
+func isResponse(p pdu.PDU) bool {
+	return p.GetHeader().CommandID < 0
+}
@@ -0,0 +1,188 @@
+package gosmpp
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+// pipeSMSC serves the peer side of a net.Pipe, answering each request with handler's result.
+func pipeSMSC(conn net.Conn, handler func(pdu.PDU) pdu.PDU) {
+	c := NewConnection(conn)
+	defer func() {
+		_ = c.Close()
+	}()
+
+	for {
+		p, err := pdu.Parse(c)
+		if err != nil {
+			return
+		}
+		if r := handler(p); r != nil {
+			if _, err = c.WritePDU(r); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func TestSubmitWithContext(t *testing.T) {
+	newPipeTransceivable := func(settings Settings, handler func(pdu.PDU) pdu.PDU) *transceivable {
+		client, server := net.Pipe()
+		go pipeSMSC(server, handler)
+
+		settings.ReadTimeout = 2 * time.Second
+		trans := newTransceivable(NewConnection(client), settings, NewDefaultStore())
+		trans.start()
+		return trans
+	}
+
+	answer := func(p pdu.PDU) pdu.PDU {
+		switch pd := p.(type) {
+		case *pdu.SubmitSM:
+			resp := pd.GetResponse().(*pdu.SubmitSMResp)
+			resp.MessageID = "abc"
+			return resp
+		case *pdu.QuerySM:
+			nack := pdu.NewGenericNack()
+			nack.SetSequenceNumber(pd.GetSequenceNumber())
+			nack.(*pdu.GenericNack).CommandStatus = data.ESME_RINVCMDID
+			return nack
+		}
+		return nil
+	}
+
+	t.Run("Response", func(t *testing.T) {
+		var unsolicited int
+		trans := newPipeTransceivable(Settings{
+			OnPDU: func(pdu.PDU, bool) {
+				unsolicited++
+			},
+		}, answer)
+		defer func() {
+			_ = trans.Close()
+		}()
+
+		req := pdu.NewSubmitSM()
+		resp, err := trans.SubmitWithContext(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, req.GetSequenceNumber(), resp.GetSequenceNumber())
+		require.Equal(t, "abc", resp.(*pdu.SubmitSMResp).MessageID)
+		require.Zero(t, unsolicited)
+	})
+
+	t.Run("Window", func(t *testing.T) {
+		expected := make(chan Response, 2)
+		trans := newPipeTransceivable(Settings{
+			WindowedRequestTracking: &WindowedRequestTracking{
+				OnExpectedPduResponse: func(r Response) {
+					expected <- r
+				},
+				OnUnexpectedPduResponse: func(p pdu.PDU) {
+					t.Error("unexpected response", p)
+				},
+				MaxWindowSize:      10,
+				StoreAccessTimeOut: 100,
+			},
+		}, answer)
+		defer func() {
+			_ = trans.Close()
+		}()
+
+		req := pdu.NewSubmitSM()
+		resp, err := trans.SubmitWithContext(context.Background(), req)
+		require.NoError(t, err)
+		require.IsType(t, &pdu.SubmitSMResp{}, resp)
+
+		// also reported as response of the window
+		select {
+		case r := <-expected:
+			require.Same(t, resp, r.PDU)
+			require.Equal(t, req.GetSequenceNumber(), r.OriginalRequest.GetSequenceNumber())
+		case <-time.After(time.Second):
+			t.Fatal("response not reported to OnExpectedPduResponse")
+		}
+		require.Empty(t, expected)
+
+		size, err := trans.GetWindowSize()
+		require.NoError(t, err)
+		require.Zero(t, size)
+	})
+
+	t.Run("WindowExpired", func(t *testing.T) {
+		var expired int32
+		trans := newPipeTransceivable(Settings{
+			WindowedRequestTracking: &WindowedRequestTracking{
+				OnExpiredPduRequest: func(pdu.PDU) bool {
+					atomic.AddInt32(&expired, 1)
+					return false
+				},
+				PduExpireTimeOut:   50 * time.Millisecond,
+				ExpireCheckTimer:   20 * time.Millisecond,
+				MaxWindowSize:      10,
+				StoreAccessTimeOut: 100,
+			},
+		}, func(pdu.PDU) pdu.PDU { return nil })
+		defer func() {
+			_ = trans.Close()
+		}()
+
+		_, err := trans.SubmitWithContext(context.Background(), pdu.NewSubmitSM())
+		require.ErrorIs(t, err, ErrRequestExpired)
+		require.Eventually(t, func() bool {
+			return atomic.LoadInt32(&expired) == 1
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("GenericNack", func(t *testing.T) {
+		trans := newPipeTransceivable(Settings{}, answer)
+		defer func() {
+			_ = trans.Close()
+		}()
+
+		resp, err := trans.SubmitWithContext(context.Background(), pdu.NewQuerySM())
+		require.Equal(t, GenericNackError{CommandStatus: data.ESME_RINVCMDID}, err)
+		require.True(t, resp.IsGNack())
+	})
+
+	t.Run("ContextDone", func(t *testing.T) {
+		trans := newPipeTransceivable(Settings{}, func(pdu.PDU) pdu.PDU { return nil })
+		defer func() {
+			_ = trans.Close()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_, err := trans.SubmitWithContext(ctx, pdu.NewSubmitSM())
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("BindLost", func(t *testing.T) {
+		trans := newPipeTransceivable(Settings{}, func(pdu.PDU) pdu.PDU { return nil })
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			_ = trans.Close()
+		}()
+
+		_, err := trans.SubmitWithContext(context.Background(), pdu.NewSubmitSM())
+		require.ErrorIs(t, err, ErrConnectionClosing)
+
+		_, err = trans.SubmitWithContext(context.Background(), pdu.NewSubmitSM())
+		require.Error(t, err)
+	})
+
+	t.Run("NoResponse", func(t *testing.T) {
+		trans := newTransceivable(nil, Settings{}, nil)
+		_, err := trans.SubmitWithContext(context.Background(), pdu.NewGenericNack())
+		require.Equal(t, ErrNoResponseExpected, err)
+	})
+}
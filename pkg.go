@@ -1,6 +1,7 @@
 package gosmpp
 
 import (
+	"context"
 	"io"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 type Transceiver interface {
 	io.Closer
 	Submit(pdu.PDU) error
+	SubmitWithContext(context.Context, pdu.PDU) (pdu.PDU, error)
 	SystemID() string
 }
 
@@ -18,6 +20,7 @@ type Transceiver interface {
 type Transmitter interface {
 	io.Closer
 	Submit(pdu.PDU) error
+	SubmitWithContext(context.Context, pdu.PDU) (pdu.PDU, error)
 	SystemID() string
 }
 
@@ -79,10 +82,67 @@ type Settings struct {
 	// OnRebind notifies `rebind` event due to State.
 	OnRebind RebindCallback
 
+	// OnRebindEndpoint notifies `rebind` event along with the SMSC endpoint bound,
+	// which may change with FailoverConnector.
+	OnRebindEndpoint RebindEndpointCallback
+
 	// SMPP Bind Window tracking feature config
 	*WindowedRequestTracking
 
+	// SequenceGenerator assigns sequence numbers to request PDUs when they are written,
+	// including enquire_link and unbind sent automatically. Responses keep sequence number of their requests.
+	// Submit returns once the number is assigned, so it can be read from the PDU afterwards.
+	//
+	// Generator is owned by the session and kept through rebinding,
+	// use pdu.NewAtomicSequenceGenerator to continue numbering from a persisted value.
+	//
+	// Nil value keeps sequence numbers assigned at PDU construction, from the process-wide counter.
+	SequenceGenerator pdu.SequenceGenerator
+
+	// Metrics collects PDU counts, response statuses and latency, window occupancy,
+	// expired requests, rebind attempts and enquire_link RTT.
+	Metrics Metrics
+
+	// RateLimiter limits throughput of billable PDUs (submit_sm, submit_multi, data_sm).
+	// Submit blocks until the PDU is allowed to be sent.
+	//
+	// Nil value disables rate limiting.
+	RateLimiter *RateLimiter
+
+	// OnRateLimitWait notifies PDU delayed by RateLimiter along with wait time.
+	OnRateLimitWait RateLimitWaitCallback
+
+	// ThrottleControl enables backing off when SMSC answers with ESME_RTHROTTLED or ESME_RMSGQFUL.
+	ThrottleControl *ThrottleControl
+
+	// ConcatReassembly enables reassembling multipart DeliverSM into a single message.
+	ConcatReassembly *ConcatReassembly
+
+	// SubmitTracing enables tracing billable requests from submission to response and delivery receipt.
+	SubmitTracing *SubmitTracing
+
+	// InboundWorkers enables handling received requests on a pool of workers,
+	// so slow callbacks do not delay reading from SMSC.
+	//
+	// Nil value handles received PDUs on the reading goroutine.
+	InboundWorkers *InboundWorkers
+
 	response func(pdu.PDU)
+
+	onResponse func(pdu.PDU) (handled, requeued bool)
+
+	// rejectBind answers bind requests with ESME_RALYBND, set on sessions bound to Server
+	rejectBind bool
+
+	reassembler *reassembler
+
+	throttler *throttler
+
+	window *windowSlots
+
+	metrics *metricsRecorder
+
+	tracing *submitTracing
 }
 
 // WindowedRequestTracking settings for TX (transmitter) and TRX (transceiver) request store.
@@ -136,6 +196,13 @@ type WindowedRequestTracking struct {
 	// Maximum value is 255
 	MaxWindowSize uint8
 
+	// BlockOnWindowFull makes Submit block until a window slot is freed, instead of
+	// failing with ErrWindowsFull. A slot is freed when the response is received or
+	// the request expires. SubmitWithContext gives up waiting once its context is done.
+	//
+	// MaxWindowSize must be set
+	BlockOnWindowFull bool
+
 	// if enabled, EnquireLink and Unbind request will be responded to automatically
 	EnableAutoRespond bool
 
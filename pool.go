@@ -0,0 +1,251 @@
+package gosmpp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+var (
+	// ErrInvalidPoolSize indicates SessionPool is created with no session.
+	ErrInvalidPoolSize = errors.New("pool size must be greater than 0")
+	// ErrNoSessionAvailable indicates all sessions of SessionPool are rebinding or closed.
+	ErrNoSessionAvailable = errors.New("no bound session available in pool")
+)
+
+// segmentAffinityTimeout is the time segments of a split message are kept on the bind of their first segment.
+const segmentAffinityTimeout = time.Minute
+
+// PoolStrategy selects session of SessionPool for each submit.
+type PoolStrategy byte
+
+const (
+	// RoundRobin submits to bound sessions in turn.
+	RoundRobin PoolStrategy = iota
+
+	// LeastWindow submits to the bound session with the least requests waiting for response.
+	// WindowedRequestTracking must be set.
+	LeastWindow
+)
+
+// SessionHealth describes a session of SessionPool.
+type SessionHealth struct {
+	// Endpoint is SMSC address of the current bind.
+	Endpoint string
+
+	// Bound indicates session is bound and accepting submits.
+	Bound bool
+
+	// Rebinding indicates session lost its bind and is binding again.
+	Rebinding bool
+
+	// WindowSize is number of requests waiting for response, zero if WindowedRequestTracking is not set.
+	WindowSize int
+}
+
+// PoolHealth describes all sessions of SessionPool.
+type PoolHealth struct {
+	Sessions []SessionHealth
+
+	// Bound is number of bound sessions.
+	Bound int
+
+	// WindowSize is number of requests waiting for response in all sessions.
+	WindowSize int
+}
+
+type segmentKey struct {
+	dest string
+	ref  uint16
+}
+
+type segmentAffinity struct {
+	session *Session
+	sent    int
+	total   byte
+	expire  time.Time
+}
+
+// SessionPool distributes submits among multiple sessions bound with the same Connector and Settings.
+//
+// Segments of a split message (concatenated by UDH or SAR TLVs) are submitted through the same session.
+type SessionPool struct {
+	sessions []*Session
+	strategy PoolStrategy
+	next     uint32
+
+	mu       sync.Mutex
+	segments map[segmentKey]*segmentAffinity
+}
+
+// NewSessionPool creates `size` sessions with NewSession, all sharing Connector, Settings and options.
+// Each session has its own request window, unless a RequestStore is shared by WithRequestStore.
+//
+// If any session fails to bind, already bound ones are closed and the error is returned.
+func NewSessionPool(c Connector, settings Settings, rebindingInterval time.Duration, size int, strategy PoolStrategy, opts ...SessionOption) (pool *SessionPool, err error) {
+	if size <= 0 {
+		return nil, ErrInvalidPoolSize
+	}
+	if strategy == LeastWindow && settings.WindowedRequestTracking == nil {
+		return nil, ErrWindowNotConfigured
+	}
+
+	pool = &SessionPool{
+		sessions: make([]*Session, 0, size),
+		strategy: strategy,
+		segments: make(map[segmentKey]*segmentAffinity),
+	}
+
+	for i := 0; i < size; i++ {
+		var session *Session
+		if session, err = NewSession(c, settings, rebindingInterval, opts...); err != nil {
+			_ = pool.Close()
+			return nil, err
+		}
+		pool.sessions = append(pool.sessions, session)
+	}
+	return
+}
+
+// Sessions returns all sessions of the pool.
+func (p *SessionPool) Sessions() []*Session {
+	return append([]*Session(nil), p.sessions...)
+}
+
+// Submit a PDU through one of bound sessions.
+func (p *SessionPool) Submit(pd pdu.PDU) error {
+	session, err := p.pick(pd)
+	if err != nil {
+		return err
+	}
+	return session.Transmitter().Submit(pd)
+}
+
+// SubmitWithContext submits a PDU through one of bound sessions and waits for its response.
+func (p *SessionPool) SubmitWithContext(ctx context.Context, pd pdu.PDU) (pdu.PDU, error) {
+	session, err := p.pick(pd)
+	if err != nil {
+		return nil, err
+	}
+	return session.SubmitWithContext(ctx, pd)
+}
+
+// GetWindowSize returns number of requests waiting for response in all sessions.
+func (p *SessionPool) GetWindowSize() (size int, err error) {
+	for _, session := range p.sessions {
+		if !session.healthy() {
+			continue
+		}
+
+		var n int
+		if n, err = session.GetWindowSize(); err != nil {
+			return 0, err
+		}
+		size += n
+	}
+	return
+}
+
+// Health returns state of all sessions.
+func (p *SessionPool) Health() (health PoolHealth) {
+	health.Sessions = make([]SessionHealth, 0, len(p.sessions))
+	for _, session := range p.sessions {
+		h := SessionHealth{
+			Bound:     session.healthy(),
+			Rebinding: atomic.LoadInt32(&session.rebinding) == 1,
+		}
+		if b := session.bound(); b != nil {
+			h.Endpoint = b.conn.Endpoint()
+		}
+		if h.Bound {
+			h.WindowSize, _ = session.GetWindowSize()
+			health.Bound++
+			health.WindowSize += h.WindowSize
+		}
+		health.Sessions = append(health.Sessions, h)
+	}
+	return
+}
+
+// Close all sessions.
+func (p *SessionPool) Close() (err error) {
+	for _, session := range p.sessions {
+		if e := session.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return
+}
+
+// pick selects session for the PDU, keeping segments of a message on the same session.
+func (p *SessionPool) pick(pd pdu.PDU) (*Session, error) {
+	sm, ok := pd.(*pdu.SubmitSM)
+	if !ok {
+		return p.choose()
+	}
+
+	ref, total, _, ok := concatInfo(&sm.Message, sm)
+	if !ok || total < 2 {
+		return p.choose()
+	}
+	key := segmentKey{dest: addressKey(sm.DestAddr), ref: ref}
+	now := time.Now()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for k, a := range p.segments {
+		if now.After(a.expire) {
+			delete(p.segments, k)
+		}
+	}
+
+	a, found := p.segments[key]
+	if !found || a.total != total || !a.session.healthy() {
+		session, err := p.choose()
+		if err != nil {
+			return nil, err
+		}
+		a = &segmentAffinity{session: session, total: total}
+		p.segments[key] = a
+	}
+
+	a.expire = now.Add(segmentAffinityTimeout)
+	if a.sent++; a.sent >= int(total) {
+		delete(p.segments, key)
+	}
+	return a.session, nil
+}
+
+// choose selects a bound session by strategy.
+func (p *SessionPool) choose() (chosen *Session, err error) {
+	start := int(atomic.AddUint32(&p.next, 1))
+
+	least := -1
+	for i := range p.sessions {
+		session := p.sessions[(start+i)%len(p.sessions)]
+		if !session.healthy() {
+			continue
+		}
+		if p.strategy == RoundRobin {
+			return session, nil
+		}
+
+		size, e := session.GetWindowSize()
+		if e != nil {
+			continue
+		}
+		if least < 0 || size < least {
+			chosen, least = session, size
+		}
+	}
+
+	if chosen == nil {
+		err = ErrNoSessionAvailable
+	}
+	return
+}
@@ -0,0 +1,171 @@
+package gosmpp
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countingServer counts submit_sm received by each bound session, optionally leaving them unanswered.
+type countingServer struct {
+	mu        sync.Mutex
+	submitted map[*ServerSession][]*pdu.SubmitSM
+}
+
+func newCountingServer(t *testing.T, respond bool) (*countingServer, string) {
+	cs := &countingServer{submitted: make(map[*ServerSession][]*pdu.SubmitSM)}
+	srv := NewServer("GoSMSC", nil, func(sess *ServerSession) Settings {
+		return Settings{
+			ReadTimeout: 2 * time.Second,
+			OnAllPDU: func(p pdu.PDU) (pdu.PDU, bool) {
+				if sm, ok := p.(*pdu.SubmitSM); ok {
+					cs.mu.Lock()
+					cs.submitted[sess] = append(cs.submitted[sess], sm)
+					cs.mu.Unlock()
+					if !respond {
+						return nil, false
+					}
+				}
+				return p.GetResponse(), false
+			},
+		}
+	})
+	return cs, startServer(t, srv)
+}
+
+func (cs *countingServer) counts() (counts []int) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	for _, submitted := range cs.submitted {
+		counts = append(counts, len(submitted))
+	}
+	return
+}
+
+func (cs *countingServer) total() (n int) {
+	for _, c := range cs.counts() {
+		n += c
+	}
+	return
+}
+
+func TestSessionPool(t *testing.T) {
+	t.Run("RoundRobin", func(t *testing.T) {
+		cs, addr := newCountingServer(t, true)
+		pool, err := NewSessionPool(TRXConnector(NonTLSDialer, Auth{SMSC: addr, SystemID: "esme"}),
+			Settings{ReadTimeout: 2 * time.Second}, -1, 3, RoundRobin)
+		require.NoError(t, err)
+		defer func() {
+			_ = pool.Close()
+		}()
+		require.Len(t, pool.Sessions(), 3)
+
+		for i := 0; i < 6; i++ {
+			require.NoError(t, pool.Submit(pdu.NewSubmitSM()))
+		}
+		require.Eventually(t, func() bool {
+			return cs.total() == 6
+		}, time.Second, 10*time.Millisecond)
+		require.Equal(t, []int{2, 2, 2}, cs.counts())
+
+		// split message stays on one bind, while other submits are spread
+		sm := pdu.NewSubmitSM().(*pdu.SubmitSM)
+		require.NoError(t, sm.DestAddr.SetAddress("1234"))
+		require.NoError(t, sm.Message.SetLongMessageWithEnc(strings.Repeat("long message ", 40), data.GSM7BIT))
+		parts, err := sm.Split()
+		require.NoError(t, err)
+		require.Greater(t, len(parts), 2)
+
+		for _, part := range parts {
+			require.NoError(t, pool.Submit(part))
+			require.NoError(t, pool.Submit(pdu.NewSubmitSM()))
+		}
+		require.Eventually(t, func() bool {
+			return cs.total() == 6+2*len(parts)
+		}, time.Second, 10*time.Millisecond)
+
+		cs.mu.Lock()
+		var carriers int
+		for _, submitted := range cs.submitted {
+			for _, p := range submitted {
+				if p.DestAddr.Address() == "1234" {
+					carriers++
+					break
+				}
+			}
+		}
+		cs.mu.Unlock()
+		require.Equal(t, 1, carriers)
+		require.Empty(t, pool.segments)
+
+		// rebinding session is skipped
+		atomic.StoreInt32(&pool.sessions[0].rebinding, 1)
+		defer atomic.StoreInt32(&pool.sessions[0].rebinding, 0)
+
+		health := pool.Health()
+		require.Equal(t, 2, health.Bound)
+		require.True(t, health.Sessions[0].Rebinding)
+		require.False(t, health.Sessions[0].Bound)
+		require.Equal(t, addr, health.Sessions[1].Endpoint)
+
+		before := cs.total()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		for i := 0; i < 4; i++ {
+			_, err = pool.SubmitWithContext(ctx, pdu.NewSubmitSM())
+			require.NoError(t, err)
+		}
+		require.Eventually(t, func() bool {
+			return cs.total() == before+4
+		}, time.Second, 10*time.Millisecond)
+
+		_, err = NewSessionPool(TRXConnector(NonTLSDialer, Auth{SMSC: addr}), Settings{ReadTimeout: time.Second}, -1, 0, RoundRobin)
+		require.Equal(t, ErrInvalidPoolSize, err)
+		_, err = NewSessionPool(TRXConnector(NonTLSDialer, Auth{SMSC: addr}), Settings{ReadTimeout: time.Second}, -1, 1, LeastWindow)
+		require.Equal(t, ErrWindowNotConfigured, err)
+	})
+
+	t.Run("LeastWindow", func(t *testing.T) {
+		cs, addr := newCountingServer(t, false)
+		pool, err := NewSessionPool(TRXConnector(NonTLSDialer, Auth{SMSC: addr, SystemID: "esme"}),
+			Settings{
+				ReadTimeout: 2 * time.Second,
+				WindowedRequestTracking: &WindowedRequestTracking{
+					MaxWindowSize:      10,
+					StoreAccessTimeOut: 100,
+				},
+			}, -1, 3, LeastWindow)
+		require.NoError(t, err)
+		defer func() {
+			_ = pool.Close()
+		}()
+
+		for i := 0; i < 6; i++ {
+			require.NoError(t, pool.Submit(pdu.NewSubmitSM()))
+			// wait until request is in window
+			require.Eventually(t, func() bool {
+				size, _ := pool.GetWindowSize()
+				return size == i+1
+			}, time.Second, 5*time.Millisecond)
+		}
+		require.Equal(t, []int{2, 2, 2}, cs.counts())
+
+		health := pool.Health()
+		require.Equal(t, 3, health.Bound)
+		require.Equal(t, 6, health.WindowSize)
+
+		// all down
+		for _, session := range pool.Sessions() {
+			_ = session.Close()
+		}
+		require.Equal(t, ErrNoSessionAvailable, pool.Submit(pdu.NewSubmitSM()))
+	})
+}
@@ -0,0 +1,148 @@
+package gosmpp
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// RateLimiter is a token-bucket limiter for billable PDUs: submit_sm, submit_multi and data_sm.
+// Other PDUs, e.g. enquire_link and responses, are never delayed.
+//
+// RateLimiter is safe for concurrent use. It could be shared between binds
+// to enforce a common throughput, and adjusted at runtime with SetRate.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	waits    uint64
+	waitTime int64
+}
+
+// RateLimiterStats is the cumulative wait time statistics of a RateLimiter.
+type RateLimiterStats struct {
+	// Waits is the number of PDUs delayed by the limiter.
+	Waits uint64
+
+	// WaitTime is the total time PDUs were delayed.
+	WaitTime time.Duration
+}
+
+// NewRateLimiter creates limiter allowing `rate` PDUs per second with bursts of at most `burst` PDUs.
+//
+// Non-positive rate disables limiting. Burst is at least 1.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	r := &RateLimiter{}
+	r.SetRate(rate, burst)
+	r.tokens = r.burst
+	return r
+}
+
+// SetRate adjusts rate and burst. Tokens accumulated so far are kept, up to the new burst.
+func (r *RateLimiter) SetRate(rate float64, burst int) {
+	if burst < 1 {
+		burst = 1
+	}
+
+	r.mu.Lock()
+	r.advance(time.Now())
+	r.rate, r.burst = rate, float64(burst)
+	if r.tokens > r.burst {
+		r.tokens = r.burst
+	}
+	r.mu.Unlock()
+}
+
+// Rate returns current rate and burst.
+func (r *RateLimiter) Rate() (rate float64, burst int) {
+	r.mu.Lock()
+	rate, burst = r.rate, int(r.burst)
+	r.mu.Unlock()
+	return
+}
+
+// Stats returns cumulative wait time statistics.
+func (r *RateLimiter) Stats() RateLimiterStats {
+	return RateLimiterStats{
+		Waits:    atomic.LoadUint64(&r.waits),
+		WaitTime: time.Duration(atomic.LoadInt64(&r.waitTime)),
+	}
+}
+
+// Wait blocks until a token is available or ctx is done.
+func (r *RateLimiter) Wait(ctx context.Context) (waited time.Duration, err error) {
+	return r.wait(ctx, nil)
+}
+
+func (r *RateLimiter) wait(ctx context.Context, abort <-chan struct{}) (waited time.Duration, err error) {
+	delay := r.reserve(time.Now())
+	if delay <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		atomic.AddUint64(&r.waits, 1)
+		atomic.AddInt64(&r.waitTime, int64(delay))
+		return delay, nil
+
+	case <-ctx.Done():
+		err = ctx.Err()
+
+	case <-abort:
+		err = ErrConnectionClosing
+	}
+
+	// give back the unused token
+	r.mu.Lock()
+	r.tokens++
+	r.mu.Unlock()
+	return
+}
+
+// reserve takes a token and returns the delay until it becomes available.
+func (r *RateLimiter) reserve(now time.Time) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.rate <= 0 {
+		return 0
+	}
+
+	r.advance(now)
+	r.tokens--
+	if r.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-r.tokens / r.rate * float64(time.Second))
+}
+
+// advance refills tokens for elapsed time. Lock must be held.
+func (r *RateLimiter) advance(now time.Time) {
+	if !r.last.IsZero() && r.rate > 0 {
+		if elapsed := now.Sub(r.last); elapsed > 0 {
+			r.tokens += elapsed.Seconds() * r.rate
+			if r.tokens > r.burst {
+				r.tokens = r.burst
+			}
+		}
+	}
+	r.last = now
+}
+
+func isBillable(p pdu.PDU) bool {
+	switch p.(type) {
+	case *pdu.SubmitSM, *pdu.SubmitMulti, *pdu.DataSM:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package gosmpp
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Run("TokenBucket", func(t *testing.T) {
+		r := NewRateLimiter(10, 2)
+		now := time.Now()
+
+		require.Zero(t, r.reserve(now))
+		require.Zero(t, r.reserve(now))
+		require.Equal(t, 100*time.Millisecond, r.reserve(now))
+		require.Equal(t, 200*time.Millisecond, r.reserve(now))
+
+		// refilled after one second, capped by burst
+		require.Zero(t, r.reserve(now.Add(time.Second)))
+		require.Zero(t, r.reserve(now.Add(time.Second)))
+		require.Equal(t, 100*time.Millisecond, r.reserve(now.Add(time.Second)))
+	})
+
+	t.Run("SetRate", func(t *testing.T) {
+		r := NewRateLimiter(1, 5)
+		r.SetRate(100, 1)
+
+		rate, burst := r.Rate()
+		require.Equal(t, float64(100), rate)
+		require.Equal(t, 1, burst)
+
+		now := time.Now()
+		require.Zero(t, r.reserve(now))
+		require.Equal(t, 10*time.Millisecond, r.reserve(now))
+
+		r.SetRate(0, 1)
+		require.Zero(t, r.reserve(now))
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		r := NewRateLimiter(0.1, 1)
+
+		_, err := r.Wait(context.Background())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = r.Wait(ctx)
+		require.Equal(t, context.DeadlineExceeded, err)
+		require.Zero(t, r.Stats().Waits)
+	})
+}
+
+func TestRateLimitedSubmit(t *testing.T) {
+	client, server := net.Pipe()
+
+	var submitted, enquired int32
+	go pipeSMSC(server, func(p pdu.PDU) pdu.PDU {
+		switch p.(type) {
+		case *pdu.SubmitSM:
+			atomic.AddInt32(&submitted, 1)
+		case *pdu.EnquireLink:
+			atomic.AddInt32(&enquired, 1)
+		}
+		return nil
+	})
+
+	limiter := NewRateLimiter(20, 1)
+
+	var waits int32
+	trans := newTransceivable(NewConnection(client), Settings{
+		ReadTimeout: 2 * time.Second,
+		RateLimiter: limiter,
+		OnRateLimitWait: func(p pdu.PDU, wait time.Duration) {
+			require.IsType(t, &pdu.SubmitSM{}, p)
+			require.Greater(t, wait, time.Duration(0))
+			atomic.AddInt32(&waits, 1)
+		},
+	}, nil)
+	trans.start()
+	defer func() {
+		_ = trans.Close()
+	}()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, trans.Submit(pdu.NewSubmitSM()))
+	}
+	require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	require.EqualValues(t, 2, atomic.LoadInt32(&waits))
+	require.EqualValues(t, 2, limiter.Stats().Waits)
+	require.GreaterOrEqual(t, limiter.Stats().WaitTime, 90*time.Millisecond)
+
+	// non billable PDU is not delayed even when tokens are exhausted
+	limiter.SetRate(0.01, 1)
+	start = time.Now()
+	require.NoError(t, trans.Submit(pdu.NewEnquireLink()))
+	require.Less(t, time.Since(start), 50*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&submitted) == 3 && atomic.LoadInt32(&enquired) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// waiting submit is aborted by closing
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- trans.Submit(pdu.NewSubmitSM())
+	}()
+	time.Sleep(20 * time.Millisecond)
+	_ = trans.Close()
+	require.Equal(t, ErrConnectionClosing, <-errCh)
+}
@@ -0,0 +1,153 @@
+package gosmpp
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+)
+
+// DefaultPermanentBindStatuses are bind_resp statuses rejecting credentials or account,
+// which rebinding would not fix.
+var DefaultPermanentBindStatuses = []data.CommandStatusType{
+	data.ESME_RINVPASWD,
+	data.ESME_RINVSYSID,
+	data.ESME_RBINDFAIL,
+}
+
+// defaultRebindInterval is used when neither RebindPolicy.InitialInterval nor
+// `rebindingInterval` of NewSession is positive, so rebinding never spins.
+const defaultRebindInterval = time.Second
+
+// RebindPolicy controls delays between rebinding attempts of Session.
+//
+// Delay after n-th failed attempt is InitialInterval * Multiplier^(n-1), capped by MaxInterval
+// and randomized by Jitter, so that many sessions do not rebind in lockstep after SMSC outage.
+type RebindPolicy struct {
+	// InitialInterval is delay after the first failed attempt.
+	//
+	// Zero value defaults to `rebindingInterval` of NewSession,
+	// or to 1 second if that is not positive either.
+	InitialInterval time.Duration
+
+	// MaxInterval caps the delay.
+	//
+	// Zero value means no cap.
+	MaxInterval time.Duration
+
+	// Multiplier grows the delay after each failed attempt. Use 1 for fixed delay.
+	//
+	// Values less than 1 default to 2.
+	Multiplier float64
+
+	// Jitter randomizes each delay by up to +/- Jitter fraction of it, e.g. 0.2 for +/- 20%.
+	//
+	// Values above 1 are treated as 1, zero or negative values disable randomization,
+	// so delay never turns negative.
+	Jitter float64
+
+	// MaxAttempts stops rebinding after the number of failed attempts.
+	//
+	// Zero value means rebinding until Session is closed.
+	MaxAttempts int
+
+	// ShouldAbort is called after each failed attempt, numbered from 1. Returning true stops rebinding.
+	//
+	// Handle is optional
+	ShouldAbort func(attempt int, err error) bool
+
+	// PermanentBindStatuses are bind_resp statuses stopping rebinding at once, as retrying
+	// could get the account locked. Session is closed and OnClosed is notified with BindRejected.
+	//
+	// Nil value defaults to DefaultPermanentBindStatuses, empty slice makes all bind errors transient.
+	PermanentBindStatuses []data.CommandStatusType
+}
+
+// WithRebindPolicy sets policy of rebinding. Rebinding is enabled even if `rebindingInterval <= 0`.
+//
+// Once attempts are exhausted or aborted, Session is closed and OnClosed is notified with RebindingAborted.
+// Bind rejected with permanent status closes Session with BindRejected.
+func WithRebindPolicy(policy RebindPolicy) SessionOption {
+	return func(s *Session) {
+		s.rebindPolicy = &policy
+	}
+}
+
+// IsPermanent checks if err is a bind failure with one of PermanentBindStatuses.
+// Joined errors, e.g. from FailoverConnector, are permanent only if all of them are.
+func (p *RebindPolicy) IsPermanent(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs := joined.Unwrap()
+		for _, e := range errs {
+			if !p.IsPermanent(e) {
+				return false
+			}
+		}
+		return len(errs) > 0
+	}
+
+	var bindErr BindError
+	if !errors.As(err, &bindErr) {
+		return false
+	}
+
+	statuses := p.PermanentBindStatuses
+	if statuses == nil {
+		statuses = DefaultPermanentBindStatuses
+	}
+	for _, status := range statuses {
+		if bindErr.CommandStatus == status {
+			return true
+		}
+	}
+	return false
+}
+
+// next returns delay after the failed attempt, false if rebinding should stop.
+func (p *RebindPolicy) next(attempt int, err error) (delay time.Duration, ok bool) {
+	if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+		return
+	}
+	if p.ShouldAbort != nil && p.ShouldAbort(attempt, err) {
+		return
+	}
+	return p.delay(attempt), true
+}
+
+// delay after n-th failed attempt.
+func (p *RebindPolicy) delay(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	limit := float64(math.MaxInt64)
+	if p.MaxInterval > 0 {
+		limit = float64(p.MaxInterval)
+	}
+
+	d := float64(p.InitialInterval)
+	if d <= 0 {
+		d = float64(defaultRebindInterval)
+	}
+	for i := 1; i < attempt && d < limit; i++ {
+		d *= multiplier
+	}
+	if d > limit {
+		d = limit
+	}
+
+	if jitter := math.Min(p.Jitter, 1); jitter > 0 {
+		d += d * jitter * (2*rand.Float64() - 1)
+	}
+	if d >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(d)
+}
@@ -0,0 +1,231 @@
+package gosmpp
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+// flakyConnector fails to connect while down.
+type flakyConnector struct {
+	Connector
+	down     int32
+	attempts int32
+}
+
+func (c *flakyConnector) Connect() (*Connection, error) {
+	atomic.AddInt32(&c.attempts, 1)
+	if atomic.LoadInt32(&c.down) == 1 {
+		return nil, errors.New("smsc is down")
+	}
+	return c.Connector.Connect()
+}
+
+func TestRebindPolicy(t *testing.T) {
+	p := RebindPolicy{InitialInterval: time.Second, MaxInterval: 10 * time.Second}
+	require.Equal(t, time.Second, p.delay(1))
+	require.Equal(t, 2*time.Second, p.delay(2))
+	require.Equal(t, 8*time.Second, p.delay(4))
+	require.Equal(t, 10*time.Second, p.delay(5))
+	require.Equal(t, 10*time.Second, p.delay(1000))
+
+	p = RebindPolicy{InitialInterval: time.Second, Multiplier: 1}
+	require.Equal(t, time.Second, p.delay(50))
+
+	p = RebindPolicy{InitialInterval: time.Hour, Multiplier: 10}
+	require.Positive(t, p.delay(1000))
+
+	// never spins without interval
+	p = RebindPolicy{}
+	require.Equal(t, defaultRebindInterval, p.delay(1))
+	p = RebindPolicy{InitialInterval: -1, Multiplier: 1}
+	require.Equal(t, defaultRebindInterval, p.delay(3))
+
+	p = RebindPolicy{InitialInterval: time.Second, Multiplier: 3, Jitter: 0.5}
+	for i := 0; i < 100; i++ {
+		d := p.delay(2)
+		require.GreaterOrEqual(t, d, 1500*time.Millisecond)
+		require.LessOrEqual(t, d, 4500*time.Millisecond)
+	}
+
+	// out of range jitter is clamped
+	p = RebindPolicy{InitialInterval: time.Second, Multiplier: 1, Jitter: -1}
+	require.Equal(t, time.Second, p.delay(3))
+	p = RebindPolicy{InitialInterval: time.Second, Multiplier: 1, Jitter: 5}
+	for i := 0; i < 100; i++ {
+		d := p.delay(2)
+		require.GreaterOrEqual(t, d, time.Duration(0))
+		require.LessOrEqual(t, d, 2*time.Second)
+	}
+
+	p = RebindPolicy{
+		InitialInterval: time.Second,
+		MaxAttempts:     3,
+		ShouldAbort: func(attempt int, err error) bool {
+			return err.Error() == "fatal"
+		},
+	}
+	_, ok := p.next(2, errors.New("refused"))
+	require.True(t, ok)
+	_, ok = p.next(3, errors.New("refused"))
+	require.False(t, ok)
+	_, ok = p.next(1, errors.New("fatal"))
+	require.False(t, ok)
+}
+
+func TestSessionRebindPolicy(t *testing.T) {
+	addr := startServer(t, newTestServer())
+	auth := Auth{SMSC: addr, SystemID: "esme", Password: "secret"}
+
+	t.Run("MaxAttempts", func(t *testing.T) {
+		c := &flakyConnector{Connector: TRXConnector(NonTLSDialer, auth)}
+
+		var rebindingErrors int32
+		closed := make(chan State, 4)
+		session, err := NewSession(c, Settings{
+			ReadTimeout: 2 * time.Second,
+			OnRebindingError: func(error) {
+				atomic.AddInt32(&rebindingErrors, 1)
+			},
+			OnClosed: func(state State) {
+				closed <- state
+			},
+		}, 0, WithRebindPolicy(RebindPolicy{
+			InitialInterval: 10 * time.Millisecond,
+			MaxAttempts:     3,
+		}))
+		require.NoError(t, err)
+		defer func() {
+			_ = session.Close()
+		}()
+
+		atomic.StoreInt32(&c.down, 1)
+		_ = session.bound().conn.Close()
+
+		require.Equal(t, InvalidStreaming, <-closed)
+		require.Equal(t, RebindingAborted, <-closed)
+		require.EqualValues(t, 3, atomic.LoadInt32(&rebindingErrors))
+		require.EqualValues(t, 4, atomic.LoadInt32(&c.attempts))
+	})
+
+	t.Run("NoInterval", func(t *testing.T) {
+		c := &flakyConnector{Connector: TRXConnector(NonTLSDialer, auth)}
+
+		failed := make(chan struct{}, 1)
+		session, err := NewSession(c, Settings{
+			ReadTimeout: 2 * time.Second,
+			OnRebindingError: func(error) {
+				select {
+				case failed <- struct{}{}:
+				default:
+				}
+			},
+		}, 0, WithRebindPolicy(RebindPolicy{MaxAttempts: 100}))
+		require.NoError(t, err)
+		defer func() {
+			_ = session.Close()
+		}()
+
+		atomic.StoreInt32(&c.down, 1)
+		_ = session.bound().conn.Close()
+
+		<-failed
+		time.Sleep(200 * time.Millisecond)
+		require.EqualValues(t, 2, atomic.LoadInt32(&c.attempts))
+	})
+
+	t.Run("CloseInterruptsWaiting", func(t *testing.T) {
+		c := &flakyConnector{Connector: TRXConnector(NonTLSDialer, auth)}
+
+		failed := make(chan struct{}, 1)
+		session, err := NewSession(c, Settings{
+			ReadTimeout: 2 * time.Second,
+			OnRebindingError: func(error) {
+				failed <- struct{}{}
+			},
+		}, time.Hour)
+		require.NoError(t, err)
+
+		atomic.StoreInt32(&c.down, 1)
+		returned := make(chan struct{})
+		go func() {
+			session.rebind()
+			close(returned)
+		}()
+		<-failed
+
+		require.NoError(t, session.Close())
+		select {
+		case <-returned:
+		case <-time.After(time.Second):
+			t.Fatal("rebinding must stop once session is closed")
+		}
+		require.EqualValues(t, 2, atomic.LoadInt32(&c.attempts))
+	})
+}
+
+func TestPermanentBindError(t *testing.T) {
+	rejected := BindError{CommandStatus: data.ESME_RINVPASWD}
+	busy := BindError{CommandStatus: data.ESME_RSYSERR}
+	down := DialError{Addr: "smsc", Err: errors.New("refused")}
+
+	p := RebindPolicy{}
+	require.True(t, p.IsPermanent(rejected))
+	require.True(t, p.IsPermanent(fmt.Errorf("bind: %w", rejected)))
+	require.False(t, p.IsPermanent(busy))
+	require.False(t, p.IsPermanent(down))
+	require.False(t, p.IsPermanent(nil))
+
+	// all endpoints must reject
+	require.True(t, p.IsPermanent(errors.Join(rejected, BindError{CommandStatus: data.ESME_RINVSYSID})))
+	require.False(t, p.IsPermanent(errors.Join(rejected, down)))
+
+	p.PermanentBindStatuses = []data.CommandStatusType{data.ESME_RSYSERR}
+	require.True(t, p.IsPermanent(busy))
+	require.False(t, p.IsPermanent(rejected))
+
+	p.PermanentBindStatuses = []data.CommandStatusType{}
+	require.False(t, p.IsPermanent(rejected))
+}
+
+func TestSessionBindRejected(t *testing.T) {
+	var reject int32
+	addr := startServer(t, NewServer("GoSMSC",
+		func(*pdu.BindRequest) data.CommandStatusType {
+			if atomic.LoadInt32(&reject) == 1 {
+				return data.ESME_RINVPASWD
+			}
+			return data.ESME_ROK
+		},
+		func(*ServerSession) Settings {
+			return Settings{ReadTimeout: 2 * time.Second}
+		},
+	))
+
+	c := &flakyConnector{Connector: TRXConnector(NonTLSDialer, Auth{SMSC: addr, SystemID: "esme", Password: "secret"})}
+	closed := make(chan State, 4)
+	session, err := NewSession(c, Settings{
+		ReadTimeout: 2 * time.Second,
+		OnClosed: func(state State) {
+			closed <- state
+		},
+	}, 10*time.Millisecond)
+	require.NoError(t, err)
+	defer func() {
+		_ = session.Close()
+	}()
+
+	atomic.StoreInt32(&reject, 1)
+	_ = session.bound().conn.Close()
+
+	require.Equal(t, InvalidStreaming, <-closed)
+	require.Equal(t, BindRejected, <-closed)
+	require.EqualValues(t, 2, atomic.LoadInt32(&c.attempts))
+}
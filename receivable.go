@@ -6,9 +6,13 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/linxGnu/gosmpp/data"
 	"github.com/linxGnu/gosmpp/pdu"
 )
 
+// minConcatCleanupInterval bounds how often incomplete multipart messages are checked for expiry.
+const minConcatCleanupInterval = 10 * time.Millisecond
+
 type receivable struct {
 	ctx          context.Context
 	cancel       context.CancelFunc
@@ -17,6 +21,10 @@ type receivable struct {
 	conn         *Connection
 	aliveState   int32
 	requestStore RequestStore
+	inbound      *inboundPool
+
+	// invalidTLV holds received requests having malformed optional parameter, until handled
+	invalidTLV sync.Map
 }
 
 func newReceivable(conn *Connection, settings Settings, requestStore RequestStore) *receivable {
@@ -26,6 +34,9 @@ func newReceivable(conn *Connection, settings Settings, requestStore RequestStor
 		requestStore: requestStore,
 	}
 	r.ctx, r.cancel = context.WithCancel(context.Background())
+	if settings.InboundWorkers != nil {
+		r.inbound = newInboundPool(*settings.InboundWorkers, r.process)
+	}
 
 	return r
 }
@@ -41,6 +52,11 @@ func (t *receivable) close(state State) (err error) {
 		// wait daemons
 		t.wg.Wait()
 
+		// wait received requests to be handled
+		if t.inbound != nil {
+			t.inbound.stop()
+		}
+
 		// close connection to notify daemons to stop
 		if state != StoppingProcessOnly {
 			err = t.conn.Close()
@@ -61,6 +77,18 @@ func (t *receivable) closing(state State) {
 }
 
 func (t *receivable) start() {
+	if t.inbound != nil {
+		t.inbound.start()
+	}
+
+	if t.settings.reassembler != nil && t.settings.ConcatReassembly.Timeout > 0 {
+		t.wg.Add(1)
+		go func() {
+			defer t.wg.Done()
+			t.concatCleanup()
+		}()
+	}
+
 	t.wg.Add(1)
 	go func() {
 		defer t.wg.Done()
@@ -68,6 +96,24 @@ func (t *receivable) start() {
 	}()
 }
 
+func (t *receivable) concatCleanup() {
+	interval := t.settings.ConcatReassembly.Timeout / 2
+	if interval < minConcatCleanupInterval {
+		interval = minConcatCleanupInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.ctx.Done():
+			return
+		case now := <-ticker.C:
+			t.settings.reassembler.expire(now)
+		}
+	}
+}
+
 func (t *receivable) loop() {
 	var err error
 	for {
@@ -80,35 +126,175 @@ func (t *receivable) loop() {
 		// read pdu from conn
 		var p pdu.PDU
 		if err = t.conn.SetReadTimeout(t.settings.ReadTimeout); err == nil {
-			p, err = pdu.Parse(t.conn)
+			p, err = t.conn.ReadPDU()
 		}
 		if err != nil {
-			if atomic.LoadInt32(&t.aliveState) == Alive {
-				if t.settings.OnReceivingError != nil {
-					t.settings.OnReceivingError(err)
+			if !isInvalidTLV(p, err) {
+				if atomic.LoadInt32(&t.aliveState) == Alive {
+					if t.settings.OnReceivingError != nil {
+						t.settings.OnReceivingError(err)
+					}
+					t.closing(InvalidStreaming)
 				}
-				t.closing(InvalidStreaming)
+				return
 			}
-			return
+
+			// PDU is read completely, the bind is kept
+			if t.settings.OnReceivingError != nil {
+				t.settings.OnReceivingError(err)
+			}
+			if !isResponse(p) {
+				// request is handled as usual, but rejected with ESME_RINVOPTPARAMVAL
+				t.invalidTLV.Store(p, struct{}{})
+			}
+			// response is processed without the malformed parameter
 		}
 
-		var closeOnUnbind bool
 		if p != nil {
-			if t.settings.WindowedRequestTracking != nil && t.settings.OnExpectedPduResponse != nil {
-				closeOnUnbind = t.handleWindowPdu(p)
-			} else if t.settings.OnAllPDU != nil {
-				closeOnUnbind = t.handleAllPdu(p)
+			if t.settings.WindowedRequestTracking != nil {
+				t.settings.metrics.received(p, t.requestStore, t.settings.StoreAccessTimeOut*time.Millisecond)
 			} else {
-				closeOnUnbind = t.handleOrClose(p)
+				t.settings.metrics.received(p, nil, 0)
+			}
+			t.settings.tracing.delivered(p)
+
+			if t.handleAwaitedPdu(p) {
+				continue
+			}
+
+			if req, ok := p.(*pdu.BindRequest); ok && t.settings.rejectBind {
+				t.rejectBind(req)
+				continue
 			}
-			if closeOnUnbind {
-				t.closing(UnbindClosing)
+
+			if t.inbound != nil && isInboundRequest(p) {
+				if !t.inbound.dispatch(t.ctx, p) {
+					return
+				}
+				continue
 			}
+
+			t.process(p)
 		}
 
 	}
 }
 
+// rejectBind answers bind request received on already bound session with ESME_RALYBND.
+func (t *receivable) rejectBind(req *pdu.BindRequest) {
+	resp := req.GetResponse().(*pdu.BindResp)
+	resp.CommandStatus = data.ESME_RALYBND
+	t.settings.response(resp)
+}
+
+// process reassembles segments of multipart message or passes received PDU to callbacks.
+func (t *receivable) process(p pdu.PDU) {
+	defer t.invalidTLV.Delete(p)
+
+	if t.settings.reassembler != nil && t.settings.reassembler.add(p) {
+		t.respond(p, p.GetResponse())
+		return
+	}
+	t.handle(p)
+}
+
+// respond sends response to the received request. Successful response to request
+// having malformed optional parameter is turned into ESME_RINVOPTPARAMVAL.
+func (t *receivable) respond(req, resp pdu.PDU) {
+	if resp != nil && resp.IsOk() {
+		if _, invalid := t.invalidTLV.Load(req); invalid {
+			if h, ok := resp.(interface {
+				SetCommandStatus(data.CommandStatusType)
+			}); ok {
+				h.SetCommandStatus(data.ESME_RINVOPTPARAMVAL)
+			}
+		}
+	}
+	t.settings.response(resp)
+}
+
+// handle passes received PDU to callbacks and closes the bind if asked to.
+func (t *receivable) handle(p pdu.PDU) {
+	var closeOnUnbind bool
+	if t.settings.WindowedRequestTracking != nil && t.settings.OnExpectedPduResponse != nil {
+		closeOnUnbind = t.handleWindowPdu(p)
+	} else if t.settings.OnAllPDU != nil {
+		closeOnUnbind = t.handleAllPdu(p)
+	} else {
+		closeOnUnbind = t.handleOrClose(p)
+	}
+	if closeOnUnbind {
+		t.closing(UnbindClosing)
+	}
+}
+
+// isInboundRequest checks if received PDU can be handled by InboundWorkers.
+// Enquire_link and unbind are kept on the reading goroutine.
+func isInboundRequest(p pdu.PDU) bool {
+	switch p.(type) {
+	case *pdu.EnquireLink, *pdu.Unbind:
+		return false
+	}
+	return !isResponse(p)
+}
+
+// handleAwaitedPdu hands the response to its waiting submitter, or re-queues the throttled request.
+// Response of a request in the window is still reported to OnExpectedPduResponse, unless re-queued.
+func (t *receivable) handleAwaitedPdu(p pdu.PDU) (handled bool) {
+	if t.settings.onResponse == nil {
+		return
+	}
+
+	var requeued bool
+	if handled, requeued = t.settings.onResponse(p); !handled {
+		return
+	}
+
+	if t.settings.WindowedRequestTracking != nil && t.requestStore != nil {
+		ctx, cancelFunc := context.WithTimeout(context.Background(), t.settings.StoreAccessTimeOut*time.Millisecond)
+		defer cancelFunc()
+		request, found := t.requestStore.Get(ctx, p.GetSequenceNumber())
+		_ = t.requestStore.Delete(ctx, p.GetSequenceNumber())
+		t.notifyWindow()
+
+		if found && !requeued && t.settings.OnExpectedPduResponse != nil && isWindowResponse(p) {
+			t.settings.OnExpectedPduResponse(Response{
+				PDU:             p,
+				OriginalRequest: request,
+			})
+		}
+	}
+	return
+}
+
+// isWindowResponse checks if the response is reported to OnExpectedPduResponse.
+// This list must match the one in handleWindowPdu.
+func isWindowResponse(p pdu.PDU) bool {
+	switch p.(type) {
+	case *pdu.CancelSMResp,
+		*pdu.DataSMResp,
+		*pdu.DeliverSMResp,
+		*pdu.EnquireLinkResp,
+		*pdu.QuerySMResp,
+		*pdu.ReplaceSMResp,
+		*pdu.SubmitMultiResp,
+		*pdu.SubmitSMResp,
+		*pdu.BroadcastSMResp,
+		*pdu.QueryBroadcastSMResp,
+		*pdu.CancelBroadcastSMResp:
+		return true
+	}
+	return false
+}
+
+// notifyWindow wakes up submitters blocked on full window and reports window occupancy.
+func (t *receivable) notifyWindow() {
+	if t.settings.window != nil {
+		t.settings.window.notify()
+	}
+	t.settings.metrics.window(t.requestStore, t.settings.StoreAccessTimeOut*time.Millisecond)
+}
+
 func (t *receivable) handleWindowPdu(p pdu.PDU) (closing bool) {
 	if t.settings.WindowedRequestTracking != nil && t.settings.OnExpectedPduResponse != nil && p != nil {
 		// This case must match the same request item list in transmittable write func
@@ -120,13 +306,17 @@ func (t *receivable) handleWindowPdu(p pdu.PDU) (closing bool) {
 			*pdu.QuerySMResp,
 			*pdu.ReplaceSMResp,
 			*pdu.SubmitMultiResp,
-			*pdu.SubmitSMResp:
+			*pdu.SubmitSMResp,
+			*pdu.BroadcastSMResp,
+			*pdu.QueryBroadcastSMResp,
+			*pdu.CancelBroadcastSMResp:
 			if t.settings.OnExpectedPduResponse != nil {
 				ctx, cancelFunc := context.WithTimeout(context.Background(), t.settings.StoreAccessTimeOut*time.Millisecond)
 				defer cancelFunc()
 				request, ok := t.requestStore.Get(ctx, p.GetSequenceNumber())
 				if ok {
 					_ = t.requestStore.Delete(ctx, p.GetSequenceNumber())
+					t.notifyWindow()
 					response := Response{
 						PDU:             p,
 						OriginalRequest: request,
@@ -138,22 +328,22 @@ func (t *receivable) handleWindowPdu(p pdu.PDU) (closing bool) {
 			}
 		case *pdu.EnquireLink:
 			if t.settings.EnableAutoRespond {
-				t.settings.response(pp.GetResponse())
+				t.respond(p, pp.GetResponse())
 			} else if t.settings.OnReceivedPduRequest != nil {
 				r, _ := t.settings.OnReceivedPduRequest(p)
-				t.settings.response(r)
+				t.respond(p, r)
 
 			}
 		case *pdu.Unbind:
 			if t.settings.EnableAutoRespond {
-				t.settings.response(pp.GetResponse())
+				t.respond(p, pp.GetResponse())
 
 				// wait to send response before closing
 				time.Sleep(50 * time.Millisecond)
 				closing = true
 			} else if t.settings.OnReceivedPduRequest != nil {
 				r, closeBind := t.settings.OnReceivedPduRequest(p)
-				t.settings.response(r)
+				t.respond(p, r)
 				if closeBind {
 					time.Sleep(50 * time.Millisecond)
 					closing = true
@@ -162,7 +352,7 @@ func (t *receivable) handleWindowPdu(p pdu.PDU) (closing bool) {
 		default:
 			if t.settings.OnReceivedPduRequest != nil {
 				r, closeBind := t.settings.OnReceivedPduRequest(p)
-				t.settings.response(r)
+				t.respond(p, r)
 				if closeBind {
 					time.Sleep(50 * time.Millisecond)
 					closing = true
@@ -176,7 +366,7 @@ func (t *receivable) handleWindowPdu(p pdu.PDU) (closing bool) {
 func (t *receivable) handleAllPdu(p pdu.PDU) (closing bool) {
 	if t.settings.OnAllPDU != nil && p != nil {
 		r, closeBind := t.settings.OnAllPDU(p)
-		t.settings.response(r)
+		t.respond(p, r)
 		if closeBind {
 			time.Sleep(50 * time.Millisecond)
 			closing = true
@@ -189,10 +379,10 @@ func (t *receivable) handleOrClose(p pdu.PDU) (closing bool) {
 	if p != nil {
 		switch pp := p.(type) {
 		case *pdu.EnquireLink:
-			t.settings.response(pp.GetResponse())
+			t.respond(p, pp.GetResponse())
 
 		case *pdu.Unbind:
-			t.settings.response(pp.GetResponse())
+			t.respond(p, pp.GetResponse())
 			// wait to send response before closing
 			time.Sleep(50 * time.Millisecond)
 
@@ -201,7 +391,7 @@ func (t *receivable) handleOrClose(p pdu.PDU) (closing bool) {
 		default:
 			var responded bool
 			if p.CanResponse() {
-				t.settings.response(p.GetResponse())
+				t.respond(p, p.GetResponse())
 				responded = true
 			}
 
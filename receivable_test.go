@@ -1,11 +1,16 @@
 package gosmpp
 
 import (
+	"context"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"net"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/errors"
 	"github.com/linxGnu/gosmpp/pdu"
 
 	"github.com/stretchr/testify/require"
@@ -276,3 +281,58 @@ func Test_receivable_handleWindowPdu(t1 *testing.T) {
 		})
 	}
 }
+
+func TestReceiveInvalidTLV(t *testing.T) {
+	client, server := net.Pipe()
+
+	received := make(chan pdu.PDU, 4)
+	go pipeSMSC(server, func(p pdu.PDU) pdu.PDU {
+		if sm, ok := p.(*pdu.SubmitSM); ok {
+			resp := sm.GetResponse()
+			resp.RegisterOptionalParam(pdu.Field{Tag: pdu.TagSourcePort, Data: []byte{0x01}})
+			return resp
+		}
+		received <- p
+		return nil
+	})
+
+	receivingErrors := make(chan error, 2)
+	handled := make(chan pdu.PDU, 1)
+	trans := newTransceivable(NewConnection(client), Settings{
+		ReadTimeout: 2 * time.Second,
+		OnPDU: func(p pdu.PDU, responded bool) {
+			if !responded {
+				t.Error("deliver_sm not responded")
+			}
+			handled <- p
+		},
+		OnReceivingError: func(err error) {
+			receivingErrors <- err
+		},
+	}, nil)
+	trans.start()
+	defer func() {
+		_ = trans.Close()
+	}()
+
+	// request is handled and rejected with its own response, bind is kept
+	sm := pdu.NewDeliverSM()
+	sm.RegisterOptionalParam(pdu.Field{Tag: pdu.TagSourcePort, Data: []byte{0x01}})
+	_, err := NewConnection(server).WritePDU(sm)
+	require.NoError(t, err)
+
+	resp := (<-received).(*pdu.DeliverSMResp)
+	require.Equal(t, data.ESME_RINVOPTPARAMVAL, resp.CommandStatus)
+	require.Equal(t, sm.GetSequenceNumber(), resp.SequenceNumber)
+	require.ErrorIs(t, <-receivingErrors, errors.ErrInvalidTLV)
+	require.Equal(t, sm.GetSequenceNumber(), (<-handled).GetSequenceNumber())
+
+	// response is handed to submitter without the malformed parameter
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	submitResp, err := trans.SubmitWithContext(ctx, pdu.NewSubmitSM())
+	require.NoError(t, err)
+	require.IsType(t, &pdu.SubmitSMResp{}, submitResp)
+	require.ErrorIs(t, <-receivingErrors, errors.ErrInvalidTLV)
+	require.Equal(t, int32(Alive), atomic.LoadInt32(&trans.aliveState))
+}
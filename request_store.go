@@ -14,6 +14,10 @@ import (
 type Request struct {
 	pdu.PDU
 	TimeSent time.Time
+
+	// Context of the request returned by SubmitTracer, nil if not traced.
+	// It is not expected to be persisted by stores serializing requests.
+	Context context.Context
 }
 
 // Response represents a response from a Request in the RequestStore
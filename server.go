@@ -0,0 +1,429 @@
+package gosmpp
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+var (
+	// ErrServerClosed is returned by Server.Serve after Server.Close.
+	ErrServerClosed = errors.New("smpp server closed")
+)
+
+// Authenticator validates a bind request sent by ESME.
+//
+// Returning status other than ESME_ROK rejects the bind with that status,
+// e.g. ESME_RINVPASWD, ESME_RINVSYSID or ESME_RBINDFAIL.
+type Authenticator func(req *pdu.BindRequest) data.CommandStatusType
+
+// ServerHandler is invoked for each successfully authenticated ESME, before bind_resp is sent.
+//
+// The returned Settings drive the bound session the same way they drive a client Session:
+// OnPDU, OnAllPDU, WindowedRequestTracking, OnClosed, etc.
+// Invalid Settings reject the bind with ESME_RBINDFAIL.
+// The session starts processing PDUs after the handler returns. Until then, and if the bind is rejected,
+// Submit, SubmitWithContext, GetWindowSize and Close of the session return ErrConnectionClosing.
+// Further bind requests of the bound ESME are answered with ESME_RALYBND, without reaching callbacks.
+type ServerHandler func(session *ServerSession) Settings
+
+// ServerOption configures Server.
+type ServerOption func(s *Server)
+
+// Server is SMSC side of SMPP, accepting binds from ESMEs.
+type Server struct {
+	systemID      string
+	authenticator Authenticator
+	handler       ServerHandler
+
+	bindTimeout      time.Duration
+	onError          ErrorCallback
+	interfaceVersion byte
+
+	tracer    PDUTracer
+	redaction Redaction
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	sessions  map[*ServerSession]struct{}
+	state     int32
+}
+
+// NewServer returns a Server.
+//
+// `systemID` is sent back to ESME within bind_resp. Nil `authenticator` accepts every bind.
+func NewServer(systemID string, authenticator Authenticator, handler ServerHandler, opts ...ServerOption) *Server {
+	s := &Server{
+		systemID:         systemID,
+		authenticator:    authenticator,
+		handler:          handler,
+		bindTimeout:      10 * time.Second,
+		interfaceVersion: data.SMPP_V50,
+		listeners:        make(map[net.Listener]struct{}),
+		sessions:         make(map[*ServerSession]struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// WithBindTimeout sets the duration a new connection has to send its bind request.
+func WithBindTimeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.bindTimeout = timeout
+	}
+}
+
+// WithServerInterfaceVersion sets the highest SMPP version supported by Server,
+// advertised to ESME as sc_interface_version within bind_resp. Default is data.SMPP_V50.
+//
+// Sessions use the lower of this version and interface_version of the bind request.
+func WithServerInterfaceVersion(version byte) ServerOption {
+	return func(s *Server) {
+		s.interfaceVersion = version
+	}
+}
+
+// WithServerErrorHandler sets callback notifying errors happened while accepting or binding connections.
+func WithServerErrorHandler(onError ErrorCallback) ServerOption {
+	return func(s *Server) {
+		s.onError = onError
+	}
+}
+
+// WithServerTracer traces all PDUs of accepted connections, including binding.
+func WithServerTracer(tracer PDUTracer, redaction Redaction) ServerOption {
+	return func(s *Server) {
+		s.tracer, s.redaction = tracer, redaction
+	}
+}
+
+// ListenAndServe listens on the TCP network address and serves incoming binds.
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// ListenAndServeTLS listens on the TCP network address and serves incoming binds over TLS.
+//
+// Failed handshakes are notified to the error handler as HandshakeError.
+func (s *Server) ListenAndServeTLS(addr string, config *tls.Config) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(tls.NewListener(l, config))
+}
+
+// Serve accepts connections on the listener and serves incoming binds.
+// Serve always returns a non-nil error and closes l.
+func (s *Server) Serve(l net.Listener) error {
+	if !s.track(l) {
+		_ = l.Close()
+		return ErrServerClosed
+	}
+	defer s.untrack(l)
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if atomic.LoadInt32(&s.state) == Closed {
+				return ErrServerClosed
+			}
+			if nErr, ok := err.(net.Error); ok && nErr.Timeout() {
+				s.notify(err)
+				continue
+			}
+			return err
+		}
+
+		go s.serveConn(conn)
+	}
+}
+
+// Close stops all listeners and closes all bound sessions.
+func (s *Server) Close() (err error) {
+	if atomic.CompareAndSwapInt32(&s.state, Alive, Closed) {
+		s.mu.Lock()
+		listeners, sessions := s.listeners, s.sessions
+		s.listeners, s.sessions = map[net.Listener]struct{}{}, map[*ServerSession]struct{}{}
+		s.mu.Unlock()
+
+		for l := range listeners {
+			if e := l.Close(); e != nil && err == nil {
+				err = e
+			}
+		}
+		for sess := range sessions {
+			_ = sess.Close()
+		}
+	}
+	return
+}
+
+// Sessions returns currently bound sessions.
+func (s *Server) Sessions() (sessions []*ServerSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sessions = make([]*ServerSession, 0, len(s.sessions))
+	for sess := range s.sessions {
+		sessions = append(sessions, sess)
+	}
+	return
+}
+
+func (s *Server) track(l net.Listener) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if atomic.LoadInt32(&s.state) == Closed {
+		return false
+	}
+	s.listeners[l] = struct{}{}
+	return true
+}
+
+func (s *Server) untrack(l net.Listener) {
+	s.mu.Lock()
+	delete(s.listeners, l)
+	s.mu.Unlock()
+	_ = l.Close()
+}
+
+func (s *Server) notify(err error) {
+	if s.onError != nil {
+		s.onError(err)
+	}
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	if err := handshake(conn, s.bindTimeout); err != nil {
+		s.notify(err)
+		_ = conn.Close()
+		return
+	}
+
+	c := NewConnection(conn)
+	c.SetTracer(s.tracer, s.redaction)
+
+	req, err := s.readBind(c)
+	if err != nil {
+		s.notify(err)
+		_ = c.Close()
+		return
+	}
+
+	status := data.ESME_ROK
+	if s.authenticator != nil {
+		status = s.authenticator(req)
+	}
+
+	c.systemID = req.SystemID
+	c.interfaceVersion = req.InterfaceVersion
+	if c.interfaceVersion > s.interfaceVersion {
+		c.interfaceVersion = s.interfaceVersion
+	}
+
+	sess := &ServerSession{
+		bindRequest: *req,
+		conn:        c,
+	}
+
+	// settings are validated before accepting the bind, so ESME is not dropped right after bind_resp
+	var settings Settings
+	if status == data.ESME_ROK {
+		if s.handler != nil {
+			settings = s.handler(sess)
+		}
+		if err = validateSettings(settings); err != nil {
+			s.notify(fmt.Errorf("session of %s: %w", req.SystemID, err))
+			status = data.ESME_RBINDFAIL
+		}
+	}
+
+	resp := pdu.NewBindResp(*req)
+	resp.CommandStatus = status
+	resp.SystemID = s.systemID
+	if status == data.ESME_ROK && req.InterfaceVersion >= data.SMPP_V34 && s.interfaceVersion >= data.SMPP_V34 {
+		// SMPP 3.3 ESME does not understand TLVs
+		resp.RegisterOptionalParam(pdu.NewUint8Field(pdu.TagScInterfaceVersion, s.interfaceVersion))
+	}
+	if _, err = c.WritePDU(resp); err == nil && status != data.ESME_ROK {
+		err = BindError{CommandStatus: status}
+	}
+	if err != nil {
+		s.notify(err)
+		_ = c.Close()
+		return
+	}
+
+	// clear bind deadlines, session settings take over from here
+	_ = c.SetDeadline(time.Time{})
+
+	var requestStore RequestStore
+	if settings.WindowedRequestTracking != nil {
+		requestStore = NewDefaultStore()
+	}
+
+	// ESME is bound already, further binds are rejected
+	settings.rejectBind = true
+
+	originalOnClosed := settings.OnClosed
+	settings.OnClosed = func(state State) {
+		if atomic.CompareAndSwapInt32(&sess.state, Alive, Closed) {
+			s.mu.Lock()
+			delete(s.sessions, sess)
+			s.mu.Unlock()
+
+			if state != ExplicitClosing {
+				// release underlying daemons and connection
+				go func() {
+					_ = sess.Close()
+				}()
+			}
+
+			if originalOnClosed != nil {
+				originalOnClosed(state)
+			}
+		}
+	}
+
+	trx := newTransceivable(c, settings, requestStore)
+
+	s.mu.Lock()
+	if atomic.LoadInt32(&s.state) == Closed {
+		s.mu.Unlock()
+		_ = c.Close()
+		return
+	}
+	// started before being visible to Close
+	trx.start()
+	sess.trx.Store(trx)
+	s.sessions[sess] = struct{}{}
+	s.mu.Unlock()
+}
+
+// readBind waits for the first bind request on a fresh connection.
+func (s *Server) readBind(c *Connection) (req *pdu.BindRequest, err error) {
+	if s.bindTimeout > 0 {
+		if err = c.SetReadTimeout(s.bindTimeout); err != nil {
+			return
+		}
+	}
+
+	for {
+		var p pdu.PDU
+		if p, err = c.ReadPDU(); isInvalidTLV(p, err) {
+			// malformed optional parameter is dropped, bind does not depend on it
+			err = nil
+		}
+		if err != nil {
+			return
+		}
+
+		switch pd := p.(type) {
+		case *pdu.BindRequest:
+			return pd, nil
+
+		case *pdu.EnquireLink:
+			_, err = c.WritePDU(pd.GetResponse())
+
+		default:
+			if !p.CanResponse() {
+				continue
+			}
+			nack := pdu.NewGenericNack()
+			nack.SetSequenceNumber(p.GetSequenceNumber())
+			nack.(*pdu.GenericNack).CommandStatus = data.ESME_RINVBNDSTS
+			_, err = c.WritePDU(nack)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// ServerSession represents an ESME bound to Server.
+type ServerSession struct {
+	bindRequest pdu.BindRequest
+	conn        *Connection
+	trx         atomic.Value // transceivable, set once the bind is accepted
+	state       int32
+}
+
+// SystemID returns SystemID of the bound ESME.
+func (s *ServerSession) SystemID() string {
+	return s.bindRequest.SystemID
+}
+
+// BindRequest returns the accepted bind request.
+func (s *ServerSession) BindRequest() pdu.BindRequest {
+	return s.bindRequest
+}
+
+// BindingType returns binding type requested by ESME.
+func (s *ServerSession) BindingType() pdu.BindingType {
+	return s.bindRequest.BindingType
+}
+
+// RemoteAddr returns the remote network address.
+func (s *ServerSession) RemoteAddr() net.Addr {
+	return s.conn.RemoteAddr()
+}
+
+// bound returns the started transceiver, ErrConnectionClosing until the bind is accepted.
+func (s *ServerSession) bound() (trx *transceivable, err error) {
+	if trx, _ = s.trx.Load().(*transceivable); trx == nil {
+		err = ErrConnectionClosing
+	}
+	return
+}
+
+// Submit a PDU to ESME.
+func (s *ServerSession) Submit(p pdu.PDU) error {
+	trx, err := s.bound()
+	if err != nil {
+		return err
+	}
+	return trx.Submit(p)
+}
+
+// SubmitWithContext submits a PDU to ESME and waits for its response.
+func (s *ServerSession) SubmitWithContext(ctx context.Context, p pdu.PDU) (pdu.PDU, error) {
+	trx, err := s.bound()
+	if err != nil {
+		return nil, err
+	}
+	return trx.SubmitWithContext(ctx, p)
+}
+
+// GetWindowSize returns number of requests waiting for response from ESME.
+func (s *ServerSession) GetWindowSize() (int, error) {
+	trx, err := s.bound()
+	if err != nil {
+		return 0, err
+	}
+	return trx.GetWindowSize()
+}
+
+// Close unbinds ESME and closes underlying connection.
+func (s *ServerSession) Close() error {
+	trx, err := s.bound()
+	if err != nil {
+		return err
+	}
+	return trx.Close()
+}
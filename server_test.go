@@ -0,0 +1,207 @@
+package gosmpp
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+// startServer serves srv on a random local port and returns its address.
+func startServer(t *testing.T, srv *Server) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() {
+		_ = srv.Serve(l)
+	}()
+	t.Cleanup(func() {
+		_ = srv.Close()
+	})
+	return l.Addr().String()
+}
+
+func TestServer(t *testing.T) {
+	var submitted, delivered int32
+
+	srv := NewServer("GoSMSC",
+		func(req *pdu.BindRequest) data.CommandStatusType {
+			if req.Password != "secret" {
+				return data.ESME_RINVPASWD
+			}
+			return data.ESME_ROK
+		},
+		func(sess *ServerSession) Settings {
+			require.Equal(t, "esme", sess.SystemID())
+			require.Equal(t, pdu.Transceiver, sess.BindingType())
+
+			return Settings{
+				ReadTimeout: 2 * time.Second,
+				OnPDU: func(p pdu.PDU, responded bool) {
+					if _, ok := p.(*pdu.SubmitSM); ok {
+						require.True(t, responded)
+						atomic.AddInt32(&submitted, 1)
+
+						// deliver back to the same ESME
+						_ = sess.Submit(pdu.NewDeliverSM())
+					}
+				},
+			}
+		},
+	)
+	addr := startServer(t, srv)
+
+	t.Run("Bind", func(t *testing.T) {
+		session, err := NewSession(
+			TRXConnector(NonTLSDialer, Auth{SMSC: addr, SystemID: "esme", Password: "secret"}),
+			Settings{
+				ReadTimeout: 2 * time.Second,
+				OnPDU: func(p pdu.PDU, responded bool) {
+					if _, ok := p.(*pdu.DeliverSM); ok {
+						require.True(t, responded)
+						atomic.AddInt32(&delivered, 1)
+					}
+				},
+			}, -1)
+		require.NoError(t, err)
+		defer func() {
+			_ = session.Close()
+		}()
+		require.Equal(t, "GoSMSC", session.Transceiver().SystemID())
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		resp, err := session.Transceiver().SubmitWithContext(ctx, pdu.NewSubmitSM())
+		require.NoError(t, err)
+		require.True(t, resp.IsOk())
+
+		require.Eventually(t, func() bool {
+			return atomic.LoadInt32(&submitted) == 1 && atomic.LoadInt32(&delivered) == 1
+		}, time.Second, 10*time.Millisecond)
+		require.Len(t, srv.Sessions(), 1)
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		_, err := TXConnector(NonTLSDialer, Auth{SMSC: addr, SystemID: "esme", Password: "wrong"}).Connect()
+		require.Equal(t, BindError{CommandStatus: data.ESME_RINVPASWD}, err)
+	})
+
+	t.Run("InvalidSettings", func(t *testing.T) {
+		notified := make(chan error, 2)
+		srv := NewServer("GoSMSC", nil, func(*ServerSession) Settings {
+			return Settings{}
+		}, WithServerErrorHandler(func(err error) {
+			notified <- err
+		}))
+
+		_, err := TXConnector(NonTLSDialer, Auth{SMSC: startServer(t, srv), SystemID: "esme"}).Connect()
+		require.Equal(t, BindError{CommandStatus: data.ESME_RBINDFAIL}, err)
+		require.ErrorContains(t, <-notified, "session of esme")
+		require.Empty(t, srv.Sessions())
+	})
+
+	t.Run("SessionBeforeBind", func(t *testing.T) {
+		errs := make(chan error, 4)
+		srv := NewServer("GoSMSC", nil, func(sess *ServerSession) Settings {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			errs <- sess.Submit(pdu.NewDeliverSM())
+			_, err := sess.SubmitWithContext(ctx, pdu.NewDeliverSM())
+			errs <- err
+			_, err = sess.GetWindowSize()
+			errs <- err
+			errs <- sess.Close()
+
+			// delivers as soon as the session starts
+			go func() {
+				for sess.Submit(pdu.NewDeliverSM()) != nil {
+					time.Sleep(time.Millisecond)
+				}
+			}()
+			return Settings{ReadTimeout: 2 * time.Second}
+		})
+
+		delivered := make(chan struct{}, 1)
+		session, err := NewSession(TRXConnector(NonTLSDialer, Auth{SMSC: startServer(t, srv), SystemID: "esme"}),
+			Settings{
+				ReadTimeout: 2 * time.Second,
+				OnPDU: func(p pdu.PDU, _ bool) {
+					if _, ok := p.(*pdu.DeliverSM); ok {
+						delivered <- struct{}{}
+					}
+				},
+			}, -1)
+		require.NoError(t, err)
+		defer func() {
+			_ = session.Close()
+		}()
+
+		for i := 0; i < 4; i++ {
+			require.Equal(t, ErrConnectionClosing, <-errs)
+		}
+		select {
+		case <-delivered:
+		case <-time.After(time.Second):
+			t.Fatal("deliver_sm not received")
+		}
+	})
+
+	t.Run("AlreadyBound", func(t *testing.T) {
+		conn, err := TRXConnector(NonTLSDialer, Auth{SMSC: addr, SystemID: "esme", Password: "secret"}).Connect()
+		require.NoError(t, err)
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		req := pdu.NewBindRequest(pdu.Transceiver)
+		req.SystemID, req.Password = "esme", "secret"
+		_, err = conn.WritePDU(req)
+		require.NoError(t, err)
+
+		require.NoError(t, conn.SetReadTimeout(time.Second))
+		p, err := conn.ReadPDU()
+		require.NoError(t, err)
+		resp := p.(*pdu.BindResp)
+		require.Equal(t, data.ESME_RALYBND, resp.CommandStatus)
+		require.Equal(t, req.SequenceNumber, resp.SequenceNumber)
+	})
+
+	t.Run("InterfaceVersion", func(t *testing.T) {
+		srv := NewServer("GoSMSC", nil, func(*ServerSession) Settings {
+			return Settings{ReadTimeout: 2 * time.Second}
+		}, WithServerInterfaceVersion(data.SMPP_V34))
+
+		conn, err := TXConnector(NonTLSDialer, Auth{SMSC: startServer(t, srv), SystemID: "esme"},
+			WithInterfaceVersion(data.SMPP_V50)).Connect()
+		require.NoError(t, err)
+		defer func() {
+			_ = conn.Close()
+		}()
+		require.Equal(t, data.SMPP_V34, conn.InterfaceVersion())
+
+		require.Eventually(t, func() bool {
+			sessions := srv.Sessions()
+			return len(sessions) == 1 && sessions[0].conn.InterfaceVersion() == data.SMPP_V34
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		require.Eventually(t, func() bool {
+			return len(srv.Sessions()) == 0
+		}, time.Second, 10*time.Millisecond)
+
+		require.NoError(t, srv.Close())
+
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		require.Equal(t, ErrServerClosed, srv.Serve(l))
+	})
+}
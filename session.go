@@ -1,6 +1,7 @@
 package gosmpp
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/linxGnu/gosmpp/pdu"
@@ -29,6 +30,17 @@ type Session struct {
 	state        int32
 	rebinding    int32
 	requestStore RequestStore
+	rebindPolicy *RebindPolicy
+
+	done chan struct{}
+}
+
+// failbackConnector is implemented by connectors able to return to a preferred SMSC endpoint.
+type failbackConnector interface {
+	failbackInterval() time.Duration
+
+	// failback binds to an endpoint preferred over the current one, nil if there is none.
+	failback() (*Connection, error)
 }
 
 type SessionOption func(session *Session)
@@ -39,26 +51,33 @@ type SessionOption func(session *Session)
 // unexpected error happened.
 //
 // `rebindingInterval` indicates duration that Session has to wait before rebinding again.
+// Use WithRebindPolicy for exponential backoff.
 //
-// Setting `rebindingInterval <= 0` will disable `auto-rebind` functionality.
+// Setting `rebindingInterval <= 0` will disable `auto-rebind` functionality, unless RebindPolicy is set.
 func NewSession(c Connector, settings Settings, rebindingInterval time.Duration, opts ...SessionOption) (session *Session, err error) {
 	// Loop through each option
 
-	if settings.ReadTimeout <= 0 || settings.ReadTimeout <= settings.EnquireLink {
-		return nil, fmt.Errorf("invalid settings: ReadTimeout must greater than max(0, EnquireLink)")
+	if err = validateSettings(settings); err != nil {
+		return
 	}
 	var requestStore RequestStore = nil
 	if settings.WindowedRequestTracking != nil {
 		requestStore = NewDefaultStore()
-		if settings.MaxWindowSize == 0 {
-			return nil, ErrWindowSizeEqualZero
-		}
-		if settings.StoreAccessTimeOut == 0 {
-			return nil, ErrStoreAccessTimeOutEqualZero
-		}
-		if settings.PduExpireTimeOut > 0 && settings.ExpireCheckTimer == 0 {
-			return nil, ErrExpireCheckTimerNotSet
-		}
+	}
+
+	if settings.ConcatReassembly != nil {
+		// shared by all binds, segments may arrive on different connections
+		settings.reassembler = newReassembler(*settings.ConcatReassembly)
+	}
+
+	if settings.ThrottleControl != nil {
+		// backoff state survives rebinding
+		settings.throttler = newThrottler(*settings.ThrottleControl)
+	}
+
+	if settings.SubmitTracing != nil {
+		// delivery receipts may arrive after rebinding
+		settings.tracing = newSubmitTracing(settings.SubmitTracing)
 	}
 
 	conn, err := c.Connect()
@@ -68,13 +87,21 @@ func NewSession(c Connector, settings Settings, rebindingInterval time.Duration,
 			rebindingInterval: rebindingInterval,
 			originalOnClosed:  settings.OnClosed,
 			requestStore:      requestStore,
+			done:              make(chan struct{}),
 		}
 
 		for _, opt := range opts {
 			opt(session)
 		}
 
-		if rebindingInterval > 0 {
+		rebindEnabled := rebindingInterval > 0 || session.rebindPolicy != nil
+		if session.rebindPolicy == nil {
+			session.rebindPolicy = &RebindPolicy{InitialInterval: rebindingInterval, Multiplier: 1}
+		} else if session.rebindPolicy.InitialInterval <= 0 {
+			session.rebindPolicy.InitialInterval = rebindingInterval
+		}
+
+		if rebindEnabled {
 			newSettings := settings
 			newSettings.OnClosed = func(state State) {
 				switch state {
@@ -97,10 +124,32 @@ func NewSession(c Connector, settings Settings, rebindingInterval time.Duration,
 		trans := newTransceivable(conn, session.settings, session.requestStore)
 		trans.start()
 		session.trx.Store(trans)
+
+		if fc, ok := c.(failbackConnector); ok && rebindEnabled && fc.failbackInterval() > 0 {
+			go session.failbackLoop(fc)
+		}
 	}
 	return
 }
 
+func validateSettings(settings Settings) error {
+	if settings.ReadTimeout <= 0 || settings.ReadTimeout <= settings.EnquireLink {
+		return fmt.Errorf("invalid settings: ReadTimeout must greater than max(0, EnquireLink)")
+	}
+	if settings.WindowedRequestTracking != nil {
+		if settings.MaxWindowSize == 0 {
+			return ErrWindowSizeEqualZero
+		}
+		if settings.StoreAccessTimeOut == 0 {
+			return ErrStoreAccessTimeOutEqualZero
+		}
+		if settings.PduExpireTimeOut > 0 && settings.ExpireCheckTimer == 0 {
+			return ErrExpireCheckTimerNotSet
+		}
+	}
+	return nil
+}
+
 func WithRequestStore(store RequestStore) SessionOption {
 	return func(s *Session) {
 		s.requestStore = store
@@ -112,6 +161,15 @@ func (s *Session) bound() *transceivable {
 	return r
 }
 
+// healthy checks if session is bound and not rebinding.
+func (s *Session) healthy() bool {
+	if atomic.LoadInt32(&s.state) != Alive || atomic.LoadInt32(&s.rebinding) != 0 {
+		return false
+	}
+	b := s.bound()
+	return b != nil && atomic.LoadInt32(&b.aliveState) == Alive
+}
+
 // Transmitter returns bound Transmitter.
 func (s *Session) Transmitter() Transmitter {
 	return s.bound()
@@ -141,6 +199,7 @@ func (s *Session) GetWindowSize() (int, error) {
 // Close session.
 func (s *Session) Close() (err error) {
 	if atomic.CompareAndSwapInt32(&s.state, Alive, Closed) {
+		close(s.done)
 		err = s.close()
 	}
 	return
@@ -157,27 +216,126 @@ func (s *Session) rebind() {
 	if atomic.CompareAndSwapInt32(&s.rebinding, 0, 1) {
 		_ = s.close()
 
-		for atomic.LoadInt32(&s.state) == Alive {
+		for attempt := 1; atomic.LoadInt32(&s.state) == Alive; attempt++ {
 			conn, err := s.c.Connect()
+			if s.settings.Metrics != nil {
+				s.settings.Metrics.RebindAttempt(err)
+			}
 			if err != nil {
 				if s.settings.OnRebindingError != nil {
 					s.settings.OnRebindingError(err)
 				}
-				time.Sleep(s.rebindingInterval)
+
+				if s.rebindPolicy.IsPermanent(err) {
+					s.abortRebinding(BindRejected)
+					return
+				}
+
+				delay, ok := s.rebindPolicy.next(attempt, err)
+				if !ok {
+					s.abortRebinding(RebindingAborted)
+					return
+				}
+
+				// Close interrupts waiting
+				timer := time.NewTimer(delay)
+				select {
+				case <-s.done:
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
 			} else {
 				// bind to session
 				trans := newTransceivable(conn, s.settings, s.requestStore)
 				trans.start()
 				s.trx.Store(trans)
 
+				// session closed meanwhile
+				if atomic.LoadInt32(&s.state) == Closed {
+					_ = trans.Close()
+					return
+				}
+
 				// reset rebinding state
 				atomic.StoreInt32(&s.rebinding, 0)
-				if s.settings.OnRebind != nil {
-					s.settings.OnRebind()
-				}
+				s.notifyRebind(conn.Endpoint())
 
 				return
 			}
 		}
 	}
 }
+
+// abortRebinding closes session which is not going to be bound again.
+func (s *Session) abortRebinding(state State) {
+	if atomic.CompareAndSwapInt32(&s.state, Alive, Closed) {
+		close(s.done)
+		if s.originalOnClosed != nil {
+			s.originalOnClosed(state)
+		}
+	}
+}
+
+// failbackLoop periodically tries to bind to a preferred endpoint, replacing the current bind on success.
+func (s *Session) failbackLoop(fc failbackConnector) {
+	ticker := time.NewTicker(fc.failbackInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+
+		// rebinding is in progress, it picks the endpoint itself
+		if atomic.LoadInt32(&s.rebinding) != 0 {
+			continue
+		}
+
+		conn, err := fc.failback()
+		if err != nil || conn == nil {
+			continue
+		}
+
+		// rebinding started meanwhile, it binds on its own
+		if !atomic.CompareAndSwapInt32(&s.rebinding, 0, 1) {
+			_ = conn.Close()
+			continue
+		}
+
+		// requests of the previous bind are failed as on closing
+		_ = s.close()
+
+		trans := newTransceivable(conn, s.settings, s.requestStore)
+		trans.start()
+		s.trx.Store(trans)
+		atomic.StoreInt32(&s.rebinding, 0)
+
+		if atomic.LoadInt32(&s.state) == Closed {
+			_ = trans.Close()
+		} else {
+			s.notifyRebind(conn.Endpoint())
+		}
+	}
+}
+
+func (s *Session) notifyRebind(endpoint string) {
+	if s.settings.OnRebind != nil {
+		s.settings.OnRebind()
+	}
+	if s.settings.OnRebindEndpoint != nil {
+		s.settings.OnRebindEndpoint(endpoint)
+	}
+}
+
+// SubmitWithContext submits a PDU through the bound Transmitter/Transceiver and waits
+// for the response carrying the same sequence number.
+func (s *Session) SubmitWithContext(ctx context.Context, p pdu.PDU) (pdu.PDU, error) {
+	b := s.bound()
+	if b == nil {
+		return nil, ErrConnectionClosing
+	}
+	return b.SubmitWithContext(ctx, p)
+}
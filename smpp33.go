@@ -0,0 +1,88 @@
+package gosmpp
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+var (
+	// ErrUnsupportedPDU indicates PDU is not supported by SMPP 3.3 bind, e.g. data_sm, bind_transceiver
+	// or message carried by message_payload TLV.
+	ErrUnsupportedPDU = errors.New("PDU is not supported by SMPP 3.3")
+)
+
+// DefaultVersionFallbackStatuses are bind_resp statuses with which legacy SMSCs commonly reject
+// SMPP 3.4 bind they do not understand.
+var DefaultVersionFallbackStatuses = []data.CommandStatusType{
+	data.ESME_RINVCMDLEN,
+	data.ESME_RINVCMDID,
+	data.ESME_RINVPARAM,
+	data.ESME_RINVPARLEN,
+}
+
+// WithVersionFallback binds again with SMPP 3.3 (interface_version 0x33) when SMSC rejects
+// the bind with one of statuses, DefaultVersionFallbackStatuses if none given.
+//
+// Transceiver cannot fall back, as SMPP 3.3 has no bind_transceiver. Not applied to OutbindConnector.
+func WithVersionFallback(statuses ...data.CommandStatusType) connectorOption {
+	if len(statuses) == 0 {
+		statuses = DefaultVersionFallbackStatuses
+	}
+	statuses = append([]data.CommandStatusType(nil), statuses...)
+
+	return func(c *connector) {
+		c.versionFallback = statuses
+	}
+}
+
+// shouldFallback checks if bind error asks for binding again with SMPP 3.3.
+func (c *connector) shouldFallback(req *pdu.BindRequest, err error) bool {
+	if len(c.versionFallback) == 0 || req.InterfaceVersion <= data.SMPP_V33_WIRE || req.BindingType == pdu.Transceiver {
+		return false
+	}
+
+	var bindErr BindError
+	if !errors.As(err, &bindErr) {
+		return false
+	}
+	for _, status := range c.versionFallback {
+		if bindErr.CommandStatus == status {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSMPP33 fails fast PDU which SMPP 3.3 SMSC would reject or misinterpret.
+// Optional parameters of other PDUs are dropped on the wire.
+func checkSMPP33(p pdu.PDU) error {
+	switch pp := p.(type) {
+	case *pdu.DataSM, *pdu.DataSMResp, *pdu.AlertNotification,
+		*pdu.BroadcastSM, *pdu.QueryBroadcastSM, *pdu.CancelBroadcastSM:
+		return fmt.Errorf("%w: %s", ErrUnsupportedPDU, p.GetHeader().CommandID)
+
+	case *pdu.BindRequest:
+		if pp.BindingType == pdu.Transceiver {
+			return fmt.Errorf("%w: %s", ErrUnsupportedPDU, pp.CommandID)
+		}
+
+	case *pdu.SubmitSM:
+		if pp.Message.IsMessagePayload() {
+			return fmt.Errorf("%w: message_payload", ErrUnsupportedPDU)
+		}
+
+	case *pdu.SubmitMulti:
+		if pp.Message.IsMessagePayload() {
+			return fmt.Errorf("%w: message_payload", ErrUnsupportedPDU)
+		}
+
+	case *pdu.DeliverSM:
+		if pp.Message.IsMessagePayload() {
+			return fmt.Errorf("%w: message_payload", ErrUnsupportedPDU)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package gosmpp
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMPP33(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		versions []byte
+		received = make(chan *pdu.SubmitSM, 1)
+	)
+	addr := startServer(t, NewServer("GoSMSC",
+		func(req *pdu.BindRequest) data.CommandStatusType {
+			mu.Lock()
+			versions = append(versions, req.InterfaceVersion)
+			mu.Unlock()
+
+			if req.InterfaceVersion != data.SMPP_V33_WIRE {
+				return data.ESME_RINVCMDID
+			}
+			return data.ESME_ROK
+		},
+		func(*ServerSession) Settings {
+			return Settings{
+				ReadTimeout: 2 * time.Second,
+				OnAllPDU: func(p pdu.PDU) (pdu.PDU, bool) {
+					if sm, ok := p.(*pdu.SubmitSM); ok {
+						received <- sm
+					}
+					return p.GetResponse(), false
+				},
+			}
+		}))
+	auth := Auth{SMSC: addr, SystemID: "esme"}
+
+	// no fallback by default
+	_, err := TXConnector(NonTLSDialer, auth).Connect()
+	var bindErr BindError
+	require.True(t, errors.As(err, &bindErr))
+	require.Equal(t, data.ESME_RINVCMDID, bindErr.CommandStatus)
+
+	// negotiate down
+	mu.Lock()
+	versions = versions[:0]
+	mu.Unlock()
+
+	session, err := NewSession(TXConnector(NonTLSDialer, auth, WithVersionFallback()),
+		Settings{ReadTimeout: 2 * time.Second}, -1)
+	require.NoError(t, err)
+	defer func() {
+		_ = session.Close()
+	}()
+	require.Equal(t, data.SMPP_V33_WIRE, session.bound().conn.InterfaceVersion())
+
+	mu.Lock()
+	require.Equal(t, []byte{data.SMPP_V34, data.SMPP_V33_WIRE}, versions)
+	mu.Unlock()
+
+	// optional params are dropped on the wire
+	sm := pdu.NewSubmitSM().(*pdu.SubmitSM)
+	require.NoError(t, sm.Message.SetMessageWithEncoding("hello", data.GSM7BIT))
+	sm.RegisterOptionalParam(pdu.NewUint16Field(pdu.TagUserMessageReference, 7))
+	require.NoError(t, session.Transmitter().Submit(sm))
+
+	select {
+	case got := <-received:
+		require.Empty(t, got.OptionalParameters)
+		msg, err := got.Message.GetMessage()
+		require.NoError(t, err)
+		require.Equal(t, "hello", msg)
+	case <-time.After(time.Second):
+		t.Fatal("submit_sm not received")
+	}
+
+	// SMPP 3.4 only PDUs fail fast
+	err = session.Transmitter().Submit(pdu.NewDataSM())
+	require.True(t, errors.Is(err, ErrUnsupportedPDU))
+
+	payload := pdu.NewSubmitSM().(*pdu.SubmitSM)
+	require.NoError(t, payload.Message.SetMessagePayloadWithEncoding("hello", data.GSM7BIT))
+	err = session.Transmitter().Submit(payload)
+	require.True(t, errors.Is(err, ErrUnsupportedPDU))
+
+	// transceiver neither binds nor falls back
+	_, err = TRXConnector(NonTLSDialer, auth, WithInterfaceVersion(data.SMPP_V33_WIRE)).Connect()
+	require.True(t, errors.Is(err, ErrUnsupportedPDU))
+
+	_, err = TRXConnector(NonTLSDialer, auth, WithVersionFallback()).Connect()
+	require.True(t, errors.As(err, &bindErr))
+
+	// bind directly with SMPP 3.3
+	mu.Lock()
+	versions = versions[:0]
+	mu.Unlock()
+
+	conn, err := RXConnector(NonTLSDialer, auth, WithInterfaceVersion(data.SMPP_V33_WIRE)).Connect()
+	require.NoError(t, err)
+	require.Equal(t, data.SMPP_V33_WIRE, conn.InterfaceVersion())
+	_ = conn.Close()
+
+	mu.Lock()
+	require.Equal(t, []byte{data.SMPP_V33_WIRE}, versions)
+	mu.Unlock()
+}
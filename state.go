@@ -29,6 +29,14 @@ const (
 
 	// UnbindClosing indicates Receiver got unbind request from SMSC and closed due to this request.
 	UnbindClosing
+
+	// RebindingAborted indicates Session gave up rebinding as RebindPolicy is exhausted or aborted.
+	// Session is closed.
+	RebindingAborted
+
+	// BindRejected indicates Session stopped rebinding as SMSC rejected the bind with
+	// one of RebindPolicy.PermanentBindStatuses, e.g. invalid password. Session is closed.
+	BindRejected
 )
 
 // String interface.
@@ -49,6 +57,12 @@ func (s *State) String() string {
 	case UnbindClosing:
 		return "UnbindClosing"
 
+	case RebindingAborted:
+		return "RebindingAborted"
+
+	case BindRejected:
+		return "BindRejected"
+
 	default:
 		return ""
 	}
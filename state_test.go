@@ -36,6 +36,16 @@ func TestState_String(t *testing.T) {
 			s:    UnbindClosing,
 			want: "UnbindClosing",
 		},
+		{
+			name: "RebindingAborted",
+			s:    RebindingAborted,
+			want: "RebindingAborted",
+		},
+		{
+			name: "BindRejected",
+			s:    BindRejected,
+			want: "BindRejected",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,249 @@
+package gosmpp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+const (
+	defaultThrottleInitialBackoff  = time.Second
+	defaultThrottleMaxBackoff      = 30 * time.Second
+	defaultThrottleRecoveryRate    = 10
+	defaultThrottleRecoveryMaxRate = 1000
+
+	// throttleTrackTimeout is the time to keep requests tracked for retry when no response arrives.
+	throttleTrackTimeout = 5 * time.Minute
+)
+
+// ThrottleControl settings for backing off when SMSC answers with ESME_RTHROTTLED or ESME_RMSGQFUL.
+//
+// After a throttled response, billable PDUs (submit_sm, submit_multi, data_sm) are paused for a backoff duration.
+// Backoff is doubled on every throttled response, up to MaxBackoff, and halved on every successful response.
+//
+// Once the pause is over, billable PDUs are sent at RecoveryRate. The rate grows by RecoveryRate
+// on every successful response, and sending is back to full speed once it reaches RecoveryMaxRate.
+type ThrottleControl struct {
+	// InitialBackoff is the pause after the first throttled response.
+	//
+	// Default: 1 second.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the pause after consecutive throttled responses.
+	//
+	// Default: 30 seconds.
+	MaxBackoff time.Duration
+
+	// RecoveryRate is the rate of billable PDUs per second allowed right after a pause,
+	// also added to the rate on every successful response.
+	//
+	// Default: 10 PDUs per second. Negative value disables ramping up, full speed is resumed after the pause.
+	RecoveryRate float64
+
+	// RecoveryMaxRate is the rate from which sending is back to full speed.
+	//
+	// Default: 1000 PDUs per second.
+	RecoveryMaxRate float64
+
+	// MaxRetries is the number of times a throttled request is re-queued automatically,
+	// keeping its sequence number. Re-queued responses are not passed to other callbacks
+	// and SubmitWithContext keeps waiting for the final response.
+	//
+	// Zero value disables re-queueing, throttled responses are handled as usual.
+	MaxRetries int
+
+	// OnThrottled notifies throttled response.
+	//
+	// Handle is optional
+	OnThrottled ThrottledCallback
+}
+
+type throttledRequest struct {
+	p       pdu.PDU
+	retries int
+	sent    time.Time
+}
+
+type throttler struct {
+	settings ThrottleControl
+
+	mu          sync.Mutex
+	backoff     time.Duration
+	pausedUntil time.Time
+	rate        float64 // zero value is full speed
+	nextSend    time.Time
+	requests    map[int32]*throttledRequest
+	pruneAt     int
+}
+
+func newThrottler(settings ThrottleControl) *throttler {
+	if settings.InitialBackoff <= 0 {
+		settings.InitialBackoff = defaultThrottleInitialBackoff
+	}
+	if settings.MaxBackoff < settings.InitialBackoff {
+		settings.MaxBackoff = defaultThrottleMaxBackoff
+		if settings.MaxBackoff < settings.InitialBackoff {
+			settings.MaxBackoff = settings.InitialBackoff
+		}
+	}
+	if settings.RecoveryRate == 0 {
+		settings.RecoveryRate = defaultThrottleRecoveryRate
+	}
+	if settings.RecoveryMaxRate <= 0 {
+		settings.RecoveryMaxRate = defaultThrottleRecoveryMaxRate
+	}
+	return &throttler{
+		settings: settings,
+		requests: make(map[int32]*throttledRequest),
+		pruneAt:  1024,
+	}
+}
+
+func isThrottled(p pdu.PDU) bool {
+	switch p.GetHeader().CommandStatus {
+	case data.ESME_RTHROTTLED, data.ESME_RMSGQFUL:
+		return true
+	}
+	return false
+}
+
+func isBillableResp(p pdu.PDU) bool {
+	switch p.(type) {
+	case *pdu.SubmitSMResp, *pdu.SubmitMultiResp, *pdu.DataSMResp:
+		return true
+	}
+	return false
+}
+
+// wait blocks while sending is paused, then paces sending while ramping up.
+func (t *throttler) wait(ctx context.Context, abort <-chan struct{}) error {
+	for {
+		t.mu.Lock()
+		now := time.Now()
+		until := t.pausedUntil
+		if t.rate > 0 && t.nextSend.After(until) {
+			until = t.nextSend
+		}
+
+		remaining := until.Sub(now)
+		if remaining <= 0 && t.rate > 0 {
+			// reserve the slot
+			if t.nextSend.Before(now) {
+				t.nextSend = now
+			}
+			t.nextSend = t.nextSend.Add(time.Duration(float64(time.Second) / t.rate))
+		}
+		t.mu.Unlock()
+
+		if remaining <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(remaining)
+		select {
+		case <-timer.C:
+			// pause might be extended meanwhile, check again
+
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+
+		case <-abort:
+			timer.Stop()
+			return ErrConnectionClosing
+		}
+	}
+}
+
+// track remembers billable request for re-queueing.
+func (t *throttler) track(p pdu.PDU) {
+	if t.settings.MaxRetries <= 0 || !isBillable(p) {
+		return
+	}
+
+	now := time.Now()
+	sequenceNumber := p.GetSequenceNumber()
+
+	t.mu.Lock()
+	if r, found := t.requests[sequenceNumber]; found && r.p == p {
+		r.sent = now // re-queued
+	} else {
+		t.requests[sequenceNumber] = &throttledRequest{p: p, sent: now}
+	}
+
+	if len(t.requests) >= t.pruneAt {
+		for seq, r := range t.requests {
+			if now.Sub(r.sent) > throttleTrackTimeout {
+				delete(t.requests, seq)
+			}
+		}
+		if t.pruneAt = 2 * len(t.requests); t.pruneAt < 1024 {
+			t.pruneAt = 1024
+		}
+	}
+	t.mu.Unlock()
+}
+
+// forget stops tracking request.
+func (t *throttler) forget(sequenceNumber int32) {
+	t.mu.Lock()
+	delete(t.requests, sequenceNumber)
+	t.mu.Unlock()
+}
+
+// handle adjusts backoff by response status. If the response is throttled and
+// the request still has retry budget, the request to re-queue is returned.
+func (t *throttler) handle(resp pdu.PDU) (requeue pdu.PDU) {
+	var (
+		req     pdu.PDU
+		retries int
+	)
+
+	sequenceNumber := resp.GetSequenceNumber()
+
+	t.mu.Lock()
+	r, found := t.requests[sequenceNumber]
+	if found {
+		delete(t.requests, sequenceNumber)
+		req, retries = r.p, r.retries
+	}
+
+	throttled := isThrottled(resp)
+	if throttled {
+		if t.backoff < t.settings.InitialBackoff {
+			t.backoff = t.settings.InitialBackoff
+		} else if t.backoff *= 2; t.backoff > t.settings.MaxBackoff {
+			t.backoff = t.settings.MaxBackoff
+		}
+		t.pausedUntil = time.Now().Add(t.backoff)
+		if t.settings.RecoveryRate > 0 {
+			t.rate = t.settings.RecoveryRate
+		}
+
+		if found && retries < t.settings.MaxRetries {
+			r.retries++
+			t.requests[sequenceNumber] = r
+			requeue = req
+		}
+	} else if resp.IsOk() && isBillableResp(resp) {
+		if t.backoff > 0 {
+			if t.backoff /= 2; t.backoff < t.settings.InitialBackoff {
+				t.backoff = 0
+			}
+		}
+		if t.rate > 0 {
+			if t.rate += t.settings.RecoveryRate; t.rate >= t.settings.RecoveryMaxRate {
+				t.rate = 0
+			}
+		}
+	}
+	t.mu.Unlock()
+
+	if throttled && t.settings.OnThrottled != nil {
+		t.settings.OnThrottled(req, resp, retries, requeue != nil)
+	}
+	return
+}
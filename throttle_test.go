@@ -0,0 +1,220 @@
+package gosmpp
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottler(t *testing.T) {
+	th := newThrottler(ThrottleControl{
+		InitialBackoff: time.Second,
+		MaxBackoff:     3 * time.Second,
+	})
+
+	respond := func(status data.CommandStatusType) {
+		resp := pdu.NewSubmitSMResp()
+		resp.(*pdu.SubmitSMResp).CommandStatus = status
+		require.Nil(t, th.handle(resp))
+	}
+
+	respond(data.ESME_RTHROTTLED)
+	require.Equal(t, time.Second, th.backoff)
+	require.True(t, th.pausedUntil.After(time.Now()))
+
+	respond(data.ESME_RMSGQFUL)
+	require.Equal(t, 2*time.Second, th.backoff)
+	respond(data.ESME_RTHROTTLED)
+	require.Equal(t, 3*time.Second, th.backoff)
+
+	require.Equal(t, float64(defaultThrottleRecoveryRate), th.rate)
+
+	// ramp back up on success
+	respond(data.ESME_ROK)
+	require.Equal(t, 1500*time.Millisecond, th.backoff)
+	require.Equal(t, float64(2*defaultThrottleRecoveryRate), th.rate)
+	respond(data.ESME_RINVDSTADR)
+	require.Equal(t, 1500*time.Millisecond, th.backoff)
+	require.Equal(t, float64(2*defaultThrottleRecoveryRate), th.rate)
+	respond(data.ESME_ROK)
+	require.Zero(t, th.backoff)
+	require.Equal(t, float64(3*defaultThrottleRecoveryRate), th.rate)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Equal(t, context.Canceled, th.wait(ctx, nil))
+}
+
+func TestThrottlerRampUp(t *testing.T) {
+	th := newThrottler(ThrottleControl{
+		InitialBackoff:  time.Millisecond,
+		RecoveryRate:    20,
+		RecoveryMaxRate: 100,
+	})
+
+	respond := func(status data.CommandStatusType) {
+		resp := pdu.NewSubmitSMResp()
+		resp.(*pdu.SubmitSMResp).CommandStatus = status
+		th.handle(resp)
+	}
+	send := func(n int) time.Duration {
+		start := time.Now()
+		for i := 0; i < n; i++ {
+			require.NoError(t, th.wait(context.Background(), nil))
+		}
+		return time.Since(start)
+	}
+
+	// full speed before throttling
+	require.Less(t, send(5), 20*time.Millisecond)
+
+	// 20/s right after the pause: 50ms between PDUs
+	respond(data.ESME_RTHROTTLED)
+	require.GreaterOrEqual(t, send(4), 150*time.Millisecond)
+
+	// 80/s after 3 successful responses
+	for i := 0; i < 3; i++ {
+		respond(data.ESME_ROK)
+	}
+	require.Equal(t, float64(80), th.rate)
+	elapsed := send(4)
+	require.GreaterOrEqual(t, elapsed, 30*time.Millisecond)
+	require.Less(t, elapsed, 150*time.Millisecond)
+
+	// full speed again
+	respond(data.ESME_ROK)
+	require.Zero(t, th.rate)
+	require.Less(t, send(5), 20*time.Millisecond)
+
+	// ramping up disabled
+	th = newThrottler(ThrottleControl{InitialBackoff: time.Millisecond, RecoveryRate: -1})
+	respond(data.ESME_RTHROTTLED)
+	require.Zero(t, th.rate)
+	time.Sleep(2 * time.Millisecond)
+	require.Less(t, send(5), 20*time.Millisecond)
+}
+
+func TestThrottleControl(t *testing.T) {
+	newPipeTransceivable := func(control *ThrottleControl, throttledTimes int32) *transceivable {
+		client, server := net.Pipe()
+
+		var received int32
+		go pipeSMSC(server, func(p pdu.PDU) pdu.PDU {
+			if _, ok := p.(*pdu.SubmitSM); !ok {
+				return nil
+			}
+			resp := p.GetResponse().(*pdu.SubmitSMResp)
+			if atomic.AddInt32(&received, 1) <= throttledTimes {
+				resp.CommandStatus = data.ESME_RTHROTTLED
+			}
+			return resp
+		})
+
+		trans := newTransceivable(NewConnection(client), Settings{
+			ReadTimeout: 2 * time.Second,
+			OnPDU: func(p pdu.PDU, _ bool) {
+				t.Fatal("response must be delivered to the submitter", p)
+			},
+			ThrottleControl: control,
+		}, nil)
+		trans.start()
+		return trans
+	}
+
+	t.Run("Requeue", func(t *testing.T) {
+		var requeued int32
+		trans := newPipeTransceivable(&ThrottleControl{
+			InitialBackoff: 50 * time.Millisecond,
+			MaxRetries:     2,
+			OnThrottled: func(req, resp pdu.PDU, retries int, ok bool) {
+				require.NotNil(t, req)
+				require.Equal(t, req.GetSequenceNumber(), resp.GetSequenceNumber())
+				require.Equal(t, int(atomic.LoadInt32(&requeued)), retries)
+				require.True(t, ok)
+				atomic.AddInt32(&requeued, 1)
+			},
+		}, 2)
+		defer func() {
+			_ = trans.Close()
+		}()
+
+		start := time.Now()
+		req := pdu.NewSubmitSM()
+		resp, err := trans.SubmitWithContext(context.Background(), req)
+		require.NoError(t, err)
+		require.True(t, resp.IsOk())
+		require.Equal(t, req.GetSequenceNumber(), resp.GetSequenceNumber())
+		require.EqualValues(t, 2, atomic.LoadInt32(&requeued))
+		require.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+	})
+
+	t.Run("RetryExhausted", func(t *testing.T) {
+		var exhausted int32
+		trans := newPipeTransceivable(&ThrottleControl{
+			InitialBackoff: 10 * time.Millisecond,
+			MaxRetries:     1,
+			OnThrottled: func(_, _ pdu.PDU, retries int, requeued bool) {
+				if !requeued {
+					require.Equal(t, 1, retries)
+					atomic.AddInt32(&exhausted, 1)
+				}
+			},
+		}, 10)
+		defer func() {
+			_ = trans.Close()
+		}()
+
+		resp, err := trans.SubmitWithContext(context.Background(), pdu.NewSubmitSM())
+		require.NoError(t, err)
+		require.EqualValues(t, data.ESME_RTHROTTLED, resp.GetHeader().CommandStatus)
+		require.EqualValues(t, 1, atomic.LoadInt32(&exhausted))
+	})
+
+	t.Run("Window", func(t *testing.T) {
+		client, server := net.Pipe()
+		var received int32
+		go pipeSMSC(server, func(p pdu.PDU) pdu.PDU {
+			resp := p.GetResponse()
+			if atomic.AddInt32(&received, 1) == 1 {
+				resp.(*pdu.SubmitSMResp).CommandStatus = data.ESME_RTHROTTLED
+			}
+			return resp
+		})
+
+		// throttled response is not reported, the request is re-queued
+		expected := make(chan Response, 2)
+		trans := newTransceivable(NewConnection(client), Settings{
+			ReadTimeout:     2 * time.Second,
+			ThrottleControl: &ThrottleControl{InitialBackoff: 10 * time.Millisecond, MaxRetries: 1},
+			WindowedRequestTracking: &WindowedRequestTracking{
+				OnExpectedPduResponse: func(r Response) {
+					expected <- r
+				},
+				MaxWindowSize:      10,
+				StoreAccessTimeOut: 100,
+			},
+		}, NewDefaultStore())
+		trans.start()
+		defer func() {
+			_ = trans.Close()
+		}()
+
+		resp, err := trans.SubmitWithContext(context.Background(), pdu.NewSubmitSM())
+		require.NoError(t, err)
+		require.True(t, resp.IsOk())
+		select {
+		case r := <-expected:
+			require.True(t, r.PDU.IsOk())
+		case <-time.After(time.Second):
+			t.Fatal("response not reported to OnExpectedPduResponse")
+		}
+		require.Empty(t, expected)
+	})
+}
@@ -0,0 +1,184 @@
+package gosmpp
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+var (
+	// ErrCertificatePinMismatch indicates none of peer certificates matches the pinned SPKI fingerprints.
+	ErrCertificatePinMismatch = errors.New("peer certificate does not match any pinned public key")
+)
+
+// tlsHandshakeTimeout limits TLS handshake done by TLSDialer.
+const tlsHandshakeTimeout = 10 * time.Second
+
+// TLSDialer returns TLS connection dialer.
+//
+// Handshake is done while dialing, so that failures are reported by Connector.Connect
+// (and OnRebindingError) as HandshakeError, distinct from DialError of network failures.
+// Config.ServerName is derived from the dialed address if empty.
+func TLSDialer(config *tls.Config) Dialer {
+	return func(addr string) (net.Conn, error) {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			return nil, DialError{Addr: addr, Err: err}
+		}
+
+		cfg := config.Clone()
+		if cfg == nil {
+			cfg = &tls.Config{}
+		}
+		if cfg.ServerName == "" {
+			if host, _, e := net.SplitHostPort(addr); e == nil {
+				cfg.ServerName = host
+			}
+		}
+
+		tlsConn := tls.Client(conn, cfg)
+		if err = handshake(tlsConn, tlsHandshakeTimeout); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		return tlsConn, nil
+	}
+}
+
+// handshake completes TLS handshake of the connection, if TLS, within timeout.
+func handshake(conn net.Conn, timeout time.Duration) (err error) {
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return
+	}
+
+	if timeout > 0 {
+		if err = tlsConn.SetDeadline(time.Now().Add(timeout)); err != nil {
+			return
+		}
+	}
+
+	if err = tlsConn.Handshake(); err != nil {
+		return HandshakeError{Addr: conn.RemoteAddr().String(), Err: err}
+	}
+	return tlsConn.SetDeadline(time.Time{})
+}
+
+// LoadClientTLSConfig returns TLS config for dialing SMSC.
+//
+// `certFile` and `keyFile` are PEM encoded client certificate and key for mutual TLS, empty to omit.
+// `caFile` is PEM encoded CA certificates verifying SMSC, empty to use system roots.
+func LoadClientTLSConfig(certFile, keyFile, caFile string) (config *tls.Config, err error) {
+	config = &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if certFile != "" || keyFile != "" {
+		var cert tls.Certificate
+		if cert, err = tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if caFile != "" {
+		if config.RootCAs, err = loadCertPool(caFile); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// LoadServerTLSConfig returns TLS config for Server or OutbindConnector listener.
+//
+// `certFile` and `keyFile` are PEM encoded server certificate and key.
+// Non-empty `clientCAFile` requires ESME to present a certificate signed by one of its CA certificates (mutual TLS).
+func LoadServerTLSConfig(certFile, keyFile, clientCAFile string) (config *tls.Config, err error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return
+	}
+
+	config = &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if clientCAFile != "" {
+		if config.ClientCAs, err = loadCertPool(clientCAFile); err != nil {
+			return nil, err
+		}
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("no certificate found in %s", file)
+	}
+	return pool, nil
+}
+
+// SPKIPin returns pin of the certificate: base64 encoded SHA-256 of its SubjectPublicKeyInfo,
+// the same as `openssl x509 -pubkey -noout | openssl pkey -pubin -outform der | openssl dgst -sha256 -binary | base64`.
+func SPKIPin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// PinSPKI returns copy of config, accepting only peers presenting a certificate matching one of the pins.
+//
+// Pinning is checked in addition to the usual verification, against any certificate of the verified chains.
+// With InsecureSkipVerify, e.g. for SMSC with self-signed certificate, only the leaf certificate
+// presented by the peer is checked. Pins are computed by SPKIPin.
+func PinSPKI(config *tls.Config, pins ...string) *tls.Config {
+	cfg := config.Clone()
+	if cfg == nil {
+		cfg = &tls.Config{}
+	}
+
+	pinned := make(map[string]struct{}, len(pins))
+	for _, pin := range pins {
+		pinned[pin] = struct{}{}
+	}
+
+	verify := cfg.VerifyConnection
+	cfg.VerifyConnection = func(state tls.ConnectionState) error {
+		if verify != nil {
+			if err := verify(state); err != nil {
+				return err
+			}
+		}
+
+		// without verification, certificates following the leaf are not bound to the peer key
+		if len(state.VerifiedChains) == 0 {
+			if len(state.PeerCertificates) > 0 {
+				if _, ok := pinned[SPKIPin(state.PeerCertificates[0])]; ok {
+					return nil
+				}
+			}
+			return ErrCertificatePinMismatch
+		}
+
+		for _, chain := range state.VerifiedChains {
+			for _, cert := range chain {
+				if _, ok := pinned[SPKIPin(cert)]; ok {
+					return nil
+				}
+			}
+		}
+		return ErrCertificatePinMismatch
+	}
+	return cfg
+}
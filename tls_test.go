@@ -0,0 +1,209 @@
+package gosmpp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestCert issues certificate signed by parent, self-signed if parent is nil.
+func newTestCert(t *testing.T, name string, parent *testCert, usage x509.ExtKeyUsage) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA, template.BasicConstraintsValid = true, true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCert{cert: cert, key: key}
+}
+
+// write stores certificate and key as PEM files, returning their paths.
+func (c *testCert) write(t *testing.T, dir, name string) (certFile, keyFile string) {
+	keyDer, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+
+	certFile, keyFile = filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+	return
+}
+
+func TestTLS(t *testing.T) {
+	dir := t.TempDir()
+
+	ca := newTestCert(t, "ca", nil, x509.ExtKeyUsageAny)
+	caFile, _ := ca.write(t, dir, "ca")
+	serverCertFile, serverKeyFile := newTestCert(t, "smsc", ca, x509.ExtKeyUsageServerAuth).write(t, dir, "smsc")
+	clientCertFile, clientKeyFile := newTestCert(t, "esme", ca, x509.ExtKeyUsageClientAuth).write(t, dir, "esme")
+
+	serverConfig, err := LoadServerTLSConfig(serverCertFile, serverKeyFile, caFile)
+	require.NoError(t, err)
+	require.Equal(t, tls.RequireAndVerifyClientCert, serverConfig.ClientAuth)
+
+	serverErrors := make(chan error, 4)
+	srv := NewServer("GoSMSC", nil, func(*ServerSession) Settings {
+		return Settings{ReadTimeout: 2 * time.Second}
+	}, WithServerErrorHandler(func(err error) {
+		serverErrors <- err
+	}))
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		_ = srv.Serve(tls.NewListener(l, serverConfig))
+	}()
+	defer func() {
+		_ = srv.Close()
+	}()
+
+	addr := l.Addr().String()
+	auth := Auth{SMSC: addr, SystemID: "esme", Password: "secret"}
+
+	t.Run("MutualTLS", func(t *testing.T) {
+		clientConfig, err := LoadClientTLSConfig(clientCertFile, clientKeyFile, caFile)
+		require.NoError(t, err)
+
+		session, err := NewSession(TRXConnector(TLSDialer(clientConfig), auth), Settings{ReadTimeout: 2 * time.Second}, -1)
+		require.NoError(t, err)
+		defer func() {
+			_ = session.Close()
+		}()
+		require.Equal(t, "GoSMSC", session.Transceiver().SystemID())
+	})
+
+	t.Run("NoClientCertificate", func(t *testing.T) {
+		clientConfig, err := LoadClientTLSConfig("", "", caFile)
+		require.NoError(t, err)
+
+		_, err = TRXConnector(TLSDialer(clientConfig), auth).Connect()
+		require.Error(t, err)
+
+		var handshakeErr HandshakeError
+		require.ErrorAs(t, <-serverErrors, &handshakeErr)
+	})
+
+	t.Run("UntrustedServer", func(t *testing.T) {
+		clientConfig, err := LoadClientTLSConfig(clientCertFile, clientKeyFile, "")
+		require.NoError(t, err)
+
+		_, err = TRXConnector(TLSDialer(clientConfig), auth).Connect()
+		var handshakeErr HandshakeError
+		require.ErrorAs(t, err, &handshakeErr)
+		require.Equal(t, addr, handshakeErr.Addr)
+		<-serverErrors
+	})
+
+	t.Run("Pinning", func(t *testing.T) {
+		clientConfig, err := LoadClientTLSConfig(clientCertFile, clientKeyFile, "")
+		require.NoError(t, err)
+		clientConfig.InsecureSkipVerify = true
+
+		// pinned to CA, which is sent by nobody
+		_, err = TRXConnector(TLSDialer(PinSPKI(clientConfig, SPKIPin(ca.cert))), auth).Connect()
+		require.True(t, errors.Is(err, ErrCertificatePinMismatch))
+		<-serverErrors
+
+		serverCert, err := tls.LoadX509KeyPair(serverCertFile, serverKeyFile)
+		require.NoError(t, err)
+		leaf, err := x509.ParseCertificate(serverCert.Certificate[0])
+		require.NoError(t, err)
+
+		conn, err := TRXConnector(TLSDialer(PinSPKI(clientConfig, "other", SPKIPin(leaf))), auth).Connect()
+		require.NoError(t, err)
+		_ = conn.Close()
+	})
+
+	t.Run("PinningVerifiedChain", func(t *testing.T) {
+		clientConfig, err := LoadClientTLSConfig(clientCertFile, clientKeyFile, caFile)
+		require.NoError(t, err)
+
+		// CA is part of the verified chain, though not sent by server
+		conn, err := TRXConnector(TLSDialer(PinSPKI(clientConfig, SPKIPin(ca.cert))), auth).Connect()
+		require.NoError(t, err)
+		_ = conn.Close()
+	})
+
+	t.Run("PinningRogueLeaf", func(t *testing.T) {
+		serverCert, err := tls.LoadX509KeyPair(serverCertFile, serverKeyFile)
+		require.NoError(t, err)
+		leaf, err := x509.ParseCertificate(serverCert.Certificate[0])
+		require.NoError(t, err)
+
+		// rogue server presents own leaf followed by the public pinned certificate
+		rogue := newTestCert(t, "rogue", nil, x509.ExtKeyUsageServerAuth)
+		rl, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		rogueSrv := NewServer("Rogue", nil, func(*ServerSession) Settings {
+			return Settings{ReadTimeout: 2 * time.Second}
+		})
+		go func() {
+			_ = rogueSrv.Serve(tls.NewListener(rl, &tls.Config{
+				Certificates: []tls.Certificate{{
+					Certificate: [][]byte{rogue.cert.Raw, leaf.Raw},
+					PrivateKey:  rogue.key,
+				}},
+			}))
+		}()
+		defer func() {
+			_ = rogueSrv.Close()
+		}()
+
+		clientConfig, err := LoadClientTLSConfig(clientCertFile, clientKeyFile, "")
+		require.NoError(t, err)
+		clientConfig.InsecureSkipVerify = true
+
+		_, err = TRXConnector(TLSDialer(PinSPKI(clientConfig, SPKIPin(leaf))),
+			Auth{SMSC: rl.Addr().String(), SystemID: "esme"}).Connect()
+		require.True(t, errors.Is(err, ErrCertificatePinMismatch))
+	})
+
+	t.Run("DialError", func(t *testing.T) {
+		closed, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		_ = closed.Close()
+
+		for _, dialer := range []Dialer{NonTLSDialer, TLSDialer(nil)} {
+			_, err = TRXConnector(dialer, Auth{SMSC: closed.Addr().String()}).Connect()
+			var dialErr DialError
+			require.ErrorAs(t, err, &dialErr)
+			var opErr *net.OpError
+			require.ErrorAs(t, err, &opErr)
+		}
+	})
+}
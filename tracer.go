@@ -0,0 +1,200 @@
+package gosmpp
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// TraceDirection is direction of traced PDU.
+type TraceDirection byte
+
+const (
+	// Inbound PDU is read from peer.
+	Inbound TraceDirection = iota
+
+	// Outbound PDU is written to peer.
+	Outbound
+)
+
+// String implements fmt.Stringer.
+func (d TraceDirection) String() string {
+	if d == Outbound {
+		return "out"
+	}
+	return "in"
+}
+
+// Redaction selects PDU content hidden from traces.
+type Redaction byte
+
+const (
+	// RedactPassword masks password of bind and outbind.
+	RedactPassword Redaction = 1 << iota
+
+	// RedactMessage masks short_message and message_payload.
+	RedactMessage
+
+	// RedactNone shows everything.
+	RedactNone Redaction = 0
+
+	// RedactAll masks password and message content.
+	RedactAll = RedactPassword | RedactMessage
+)
+
+const redactedValue = "[REDACTED]"
+
+// PDUTrace is a PDU passing through Connection.
+type PDUTrace struct {
+	Direction      TraceDirection
+	CommandID      data.CommandIDType
+	CommandStatus  data.CommandStatusType
+	SequenceNumber int32
+
+	// Fields are decoded PDU fields, including optional parameters keyed by
+	// their hexadecimal tag, e.g. `tlv_0204`.
+	Fields map[string]interface{}
+
+	// HexDump of the PDU on the wire. Redacted octets are masked, lengths are kept.
+	HexDump string
+
+	// Err is the error writing outbound PDU, or reading and parsing inbound PDU, if any.
+	// Fields of inbound PDU failed to parse are empty or incomplete.
+	Err error
+}
+
+// PDUTracer records PDUs passing through Connection.
+//
+// TracePDU is called synchronously from reading/writing goroutine and must not block.
+type PDUTracer interface {
+	TracePDU(PDUTrace)
+}
+
+// PDUTracerFunc adapts function to PDUTracer.
+type PDUTracerFunc func(PDUTrace)
+
+// TracePDU implements PDUTracer.
+func (f PDUTracerFunc) TracePDU(t PDUTrace) {
+	f(t)
+}
+
+// WithTracer traces all PDUs of connections made by connector, including binding.
+func WithTracer(tracer PDUTracer, redaction Redaction) connectorOption {
+	return func(c *connector) {
+		c.tracer, c.redaction = tracer, redaction
+	}
+}
+
+// newPDUTrace decodes outbound PDU after redaction. Raw is the PDU as marshalled for writing.
+func newPDUTrace(p pdu.PDU, raw *pdu.ByteBuffer, redaction Redaction) PDUTrace {
+	header := p.GetHeader()
+
+	password, message := redaction&RedactPassword != 0, redaction&RedactMessage != 0
+
+	redacted := pdu.Redacted(p, password, message)
+	if redacted != p {
+		omit := raw.OmitOptionalParams
+		raw = pdu.NewBuffer(make([]byte, 0, raw.Len()))
+		raw.OmitOptionalParams = omit
+		redacted.Marshal(raw)
+	}
+
+	return PDUTrace{
+		Direction:      Outbound,
+		CommandID:      header.CommandID,
+		CommandStatus:  header.CommandStatus,
+		SequenceNumber: header.SequenceNumber,
+		Fields:         decodeFields(redacted, password, message),
+		HexDump:        raw.HexDump(),
+	}
+}
+
+// newInboundTrace decodes PDU read from the wire after redaction. Frame is the PDU as read,
+// p is parsed from it and is nil or incomplete if reading or parsing failed with err.
+func newInboundTrace(frame []byte, p pdu.PDU, err error, redaction Redaction) PDUTrace {
+	var headerBytes [16]byte
+	copy(headerBytes[:], frame)
+	header := pdu.ParseHeader(headerBytes)
+
+	password, message := redaction&RedactPassword != 0, redaction&RedactMessage != 0
+
+	trace := PDUTrace{
+		Direction:      Inbound,
+		CommandID:      header.CommandID,
+		CommandStatus:  header.CommandStatus,
+		SequenceNumber: header.SequenceNumber,
+		HexDump:        hex.EncodeToString(pdu.RedactFrame(frame, p, password, message)),
+		Err:            err,
+	}
+	if p != nil {
+		trace.Fields = decodeFields(pdu.Redacted(p, password, message), password, message)
+	}
+	return trace
+}
+
+// decodeFields returns exported fields of PDU and its optional parameters.
+// Values of duplicated optional parameters are listed in wire order.
+func decodeFields(p pdu.PDU, password, message bool) map[string]interface{} {
+	fields := make(map[string]interface{})
+
+	v := reflect.Indirect(reflect.ValueOf(p))
+	if v.Kind() == reflect.Struct {
+		typ := v.Type()
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			switch {
+			case !f.IsExported():
+			case f.Name == "Password" && password:
+				fields[f.Name] = redactedValue
+			default:
+				fields[f.Name] = decodeField(v.Field(i).Interface(), message)
+			}
+		}
+	}
+
+	if params, ok := p.(interface{ OptionalParams() []pdu.Field }); ok {
+		for _, field := range params.OptionalParams() {
+			key, value := "tlv_"+field.Tag.Hex(), interface{}(hex.EncodeToString(field.Data))
+			if message && field.Tag == pdu.TagMessagePayload {
+				value = redactedValue
+			}
+
+			switch existing := fields[key].(type) {
+			case nil:
+				fields[key] = value
+			case []interface{}:
+				fields[key] = append(existing, value)
+			default:
+				fields[key] = []interface{}{existing, value}
+			}
+		}
+	}
+
+	return fields
+}
+
+func decodeField(v interface{}, message bool) interface{} {
+	switch value := v.(type) {
+	case pdu.Address:
+		return addressKey(value)
+
+	case pdu.ShortMessage:
+		if message {
+			return redactedValue
+		}
+		if message, err := value.GetMessage(); err == nil {
+			return message
+		}
+		d, _ := value.GetMessageData()
+		return hex.EncodeToString(d)
+
+	case string, byte, int32, bool:
+		return value
+
+	default:
+		return fmt.Sprintf("%+v", value)
+	}
+}
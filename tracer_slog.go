@@ -0,0 +1,44 @@
+//go:build go1.21
+
+package gosmpp
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+)
+
+// NewSlogTracer returns PDUTracer logging every PDU to logger at the given level.
+func NewSlogTracer(logger *slog.Logger, level slog.Level) PDUTracer {
+	return PDUTracerFunc(func(t PDUTrace) {
+		ctx := context.Background()
+		if !logger.Enabled(ctx, level) {
+			return
+		}
+
+		keys := make([]string, 0, len(t.Fields))
+		for key := range t.Fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		fields := make([]any, 0, len(keys))
+		for _, key := range keys {
+			fields = append(fields, slog.Any(key, t.Fields[key]))
+		}
+
+		attrs := []slog.Attr{
+			slog.String("direction", t.Direction.String()),
+			slog.String("command_id", t.CommandID.String()),
+			slog.String("command_status", t.CommandStatus.String()),
+			slog.Int("sequence_number", int(t.SequenceNumber)),
+			slog.Group("fields", fields...),
+			slog.String("hex", t.HexDump),
+		}
+		if t.Err != nil {
+			attrs = append(attrs, slog.Any("error", t.Err))
+		}
+
+		logger.LogAttrs(ctx, level, "smpp pdu", attrs...)
+	})
+}
@@ -0,0 +1,37 @@
+//go:build go1.21
+
+package gosmpp
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlogTracer(t *testing.T) {
+	var buf bytes.Buffer
+	tracer := NewSlogTracer(slog.New(slog.NewJSONHandler(&buf, nil)), slog.LevelInfo)
+
+	bindReq := pdu.NewBindRequest(pdu.Transmitter)
+	bindReq.SystemID = "esme"
+	bindReq.Password = "secret"
+	tracer.TracePDU(newPDUTrace(bindReq, marshalled(bindReq), RedactAll))
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	require.Equal(t, "smpp pdu", record["msg"])
+	require.Equal(t, "out", record["direction"])
+	require.Equal(t, "BIND_TRANSMITTER", record["command_id"])
+	require.Equal(t, redactedValue, record["fields"].(map[string]interface{})["Password"])
+	require.NotContains(t, buf.String(), "secret")
+
+	// disabled level
+	buf.Reset()
+	NewSlogTracer(slog.New(slog.NewJSONHandler(&buf, nil)), slog.LevelDebug).TracePDU(PDUTrace{})
+	require.Zero(t, buf.Len())
+}
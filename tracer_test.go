@@ -0,0 +1,146 @@
+package gosmpp
+
+import (
+	"encoding/hex"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/errors"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracer(t *testing.T) {
+	client, server := net.Pipe()
+
+	var (
+		mu     sync.Mutex
+		traces []PDUTrace
+	)
+	tracer := PDUTracerFunc(func(trace PDUTrace) {
+		mu.Lock()
+		traces = append(traces, trace)
+		mu.Unlock()
+	})
+
+	out := NewConnection(client)
+	out.SetTracer(tracer, RedactAll)
+	in := NewConnection(server)
+	in.SetTracer(tracer, RedactNone)
+
+	bindReq := pdu.NewBindRequest(pdu.Transceiver)
+	bindReq.SystemID = "esme"
+	bindReq.Password = "secret"
+
+	written := make(chan error, 1)
+	go func() {
+		_, err := out.WritePDU(bindReq)
+		written <- err
+	}()
+	p, err := in.ReadPDU()
+	require.NoError(t, err)
+	require.NoError(t, <-written)
+	require.Equal(t, "secret", p.(*pdu.BindRequest).Password)
+
+	require.Len(t, traces, 2)
+	sent, received := traces[0], traces[1]
+	if sent.Direction != Outbound {
+		sent, received = received, sent
+	}
+
+	require.Equal(t, Outbound, sent.Direction)
+	require.Equal(t, data.BIND_TRANSCEIVER, sent.CommandID)
+	require.Equal(t, bindReq.GetSequenceNumber(), sent.SequenceNumber)
+	require.Equal(t, "esme", sent.Fields["SystemID"])
+	require.Equal(t, redactedValue, sent.Fields["Password"])
+	require.NotContains(t, sent.HexDump, "736563726574") // "secret"
+	require.Contains(t, sent.HexDump, "2a2a2a2a2a2a")    // masked
+
+	require.Equal(t, Inbound, received.Direction)
+	require.Equal(t, "secret", received.Fields["Password"])
+	require.Contains(t, received.HexDump, "736563726574")
+	require.Equal(t, len(sent.HexDump), len(received.HexDump))
+
+	t.Run("message", func(t *testing.T) {
+		sm := pdu.NewSubmitSM().(*pdu.SubmitSM)
+		require.NoError(t, sm.Message.SetMessageWithEncoding("confidential", data.UCS2))
+		sm.RegisterOptionalParam(pdu.NewUint16Field(pdu.TagUserMessageReference, 1))
+		sm.RegisterOptionalParam(pdu.NewUint16Field(pdu.TagUserMessageReference, 2))
+		sm.AddOptionalParam(pdu.NewUint16Field(pdu.TagUserMessageReference, 3))
+
+		redacted := newPDUTrace(sm, marshalled(sm), RedactMessage)
+		require.Equal(t, redactedValue, redacted.Fields["Message"])
+		require.Equal(t, []interface{}{"0002", "0003"}, redacted.Fields["tlv_0204"])
+
+		plain := newPDUTrace(sm, marshalled(sm), RedactPassword)
+		require.Equal(t, "confidential", plain.Fields["Message"])
+		require.True(t, strings.Contains(plain.HexDump, "0063006f006e0066"))
+		require.False(t, strings.Contains(redacted.HexDump, "0063006f006e0066"))
+	})
+
+	t.Run("frame", func(t *testing.T) {
+		readFrame := func(frame []byte, redaction Redaction) (pdu.PDU, PDUTrace, error) {
+			client, server := net.Pipe()
+			defer func() {
+				_ = client.Close()
+			}()
+			go func() {
+				_, _ = server.Write(frame)
+				_ = server.Close()
+			}()
+
+			var trace PDUTrace
+			in := NewConnection(client)
+			in.SetTracer(PDUTracerFunc(func(t PDUTrace) {
+				trace = t
+			}), redaction)
+			p, err := in.ReadPDU()
+			return p, trace, err
+		}
+
+		// bytes on the wire are dumped, not the re-encoded PDU
+		sm := pdu.NewDeliverSM()
+		sm.RegisterOptionalParam(pdu.Field{Tag: pdu.TagSourcePort, Data: []byte{0x01}})
+		frame := marshalled(sm).Bytes()
+
+		p, trace, err := readFrame(frame, RedactNone)
+		require.ErrorIs(t, err, errors.ErrInvalidTLV)
+		require.Equal(t, err, trace.Err)
+		require.Equal(t, hex.EncodeToString(frame), trace.HexDump)
+		require.Equal(t, int32(len(frame)), p.GetHeader().CommandLength)
+
+		// frames failed to parse are traced
+		unknown := append([]byte(nil), frame...)
+		unknown[7] = 0xff
+		p, trace, err = readFrame(unknown, RedactNone)
+		require.Error(t, err)
+		require.Nil(t, p)
+		require.Equal(t, err, trace.Err)
+		require.Equal(t, hex.EncodeToString(unknown), trace.HexDump)
+
+		// unknown content is masked
+		_, trace, _ = readFrame(unknown, RedactAll)
+		require.Equal(t, hex.EncodeToString(unknown[:16])+strings.Repeat("2a", len(unknown)-16), trace.HexDump)
+
+		// password is masked in place
+		bindReq := pdu.NewBindRequest(pdu.Transceiver)
+		bindReq.SystemID = "esme"
+		bindReq.Password = "secret"
+		frame = marshalled(bindReq).Bytes()
+
+		_, trace, err = readFrame(frame, RedactPassword)
+		require.NoError(t, err)
+		require.Equal(t, strings.Replace(hex.EncodeToString(frame), "736563726574", "2a2a2a2a2a2a", 1), trace.HexDump)
+	})
+}
+
+// marshalled returns PDU as written on the wire.
+func marshalled(p pdu.PDU) *pdu.ByteBuffer {
+	buf := pdu.NewBuffer(nil)
+	p.Marshal(buf)
+	return buf
+}
@@ -0,0 +1,237 @@
+package gosmpp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+)
+
+// SubmitTracer traces billable requests (submit_sm, submit_multi, data_sm) from submission
+// to their response and, optionally, to the delivery receipt. Implementation must be safe for concurrent use.
+//
+// Package github.com/linxGnu/gosmpp/tracing/opentelemetry provides OpenTelemetry spans.
+type SubmitTracer interface {
+	// StartSubmit is called once request is submitted, with context given to SubmitWithContext
+	// or context.Background() for Submit. Returned context, usually carrying a span,
+	// is passed to the following calls and kept in Request.Context.
+	StartSubmit(ctx context.Context, req pdu.PDU) context.Context
+
+	// EndSubmit is called with response of the request, or with error if no response
+	// is going to be received: request failed to be written, expired in the window or the bind is closing.
+	EndSubmit(ctx context.Context, resp pdu.PDU, err error)
+
+	// DeliveryReceipt is called on delivery receipt for message id returned to a traced request.
+	DeliveryReceipt(ctx context.Context, deliver *pdu.DeliverSM, receipt pdu.DeliveryReceipt)
+}
+
+// SubmitTracing settings for tracing submitted requests with SubmitTracer.
+type SubmitTracing struct {
+	// Tracer traces submitted requests.
+	Tracer SubmitTracer
+
+	// ReceiptTimeout is the time message id of successfully responded request is remembered,
+	// waiting for its delivery receipt.
+	//
+	// Zero duration disables linking delivery receipts.
+	ReceiptTimeout time.Duration
+
+	// MaxPendingReceipts limits number of message ids waiting for delivery receipt.
+	// When exceeded, the oldest one is forgotten.
+	//
+	// Zero value means no limit.
+	MaxPendingReceipts int
+}
+
+type pendingReceipt struct {
+	ctx    context.Context
+	expire time.Time
+}
+
+type receiptEntry struct {
+	messageID string
+	expire    time.Time
+}
+
+// submitTracing keeps context of traced requests, by sequence number until responded
+// and by message id until delivery receipt. Nil tracing traces nothing.
+type submitTracing struct {
+	settings SubmitTracing
+
+	mu       sync.Mutex
+	inflight map[int32]context.Context
+	receipts map[string]pendingReceipt
+	queue    []receiptEntry // ordered by expire
+}
+
+func newSubmitTracing(settings *SubmitTracing) *submitTracing {
+	if settings == nil || settings.Tracer == nil {
+		return nil
+	}
+	return &submitTracing{
+		settings: *settings,
+		inflight: make(map[int32]context.Context),
+		receipts: make(map[string]pendingReceipt),
+	}
+}
+
+// start traces submitted request. Request already traced, e.g. re-queued after throttling, keeps its context.
+func (s *submitTracing) start(ctx context.Context, p pdu.PDU) {
+	if s == nil || !isBillable(p) {
+		return
+	}
+
+	s.mu.Lock()
+	_, found := s.inflight[p.GetSequenceNumber()]
+	s.mu.Unlock()
+	if found {
+		return
+	}
+
+	ctx = s.settings.Tracer.StartSubmit(ctx, p)
+
+	s.mu.Lock()
+	s.inflight[p.GetSequenceNumber()] = ctx
+	s.mu.Unlock()
+}
+
+// context returns context of traced request, nil if not traced.
+func (s *submitTracing) context(p pdu.PDU) (ctx context.Context) {
+	if s != nil {
+		s.mu.Lock()
+		ctx = s.inflight[p.GetSequenceNumber()]
+		s.mu.Unlock()
+	}
+	return
+}
+
+func (s *submitTracing) take(sequenceNumber int32) (ctx context.Context, found bool) {
+	s.mu.Lock()
+	if ctx, found = s.inflight[sequenceNumber]; found {
+		delete(s.inflight, sequenceNumber)
+	}
+	s.mu.Unlock()
+	return
+}
+
+// responded ends tracing of the request answered by resp, remembering its message id for delivery receipt.
+func (s *submitTracing) responded(resp pdu.PDU) {
+	if s == nil {
+		return
+	}
+
+	ctx, found := s.take(resp.GetSequenceNumber())
+	if !found {
+		return
+	}
+	s.settings.Tracer.EndSubmit(ctx, resp, nil)
+
+	if s.settings.ReceiptTimeout <= 0 || resp.GetHeader().CommandStatus != data.ESME_ROK {
+		return
+	}
+
+	var messageID string
+	switch r := resp.(type) {
+	case *pdu.SubmitSMResp:
+		messageID = r.MessageID
+	case *pdu.DataSMResp:
+		messageID = r.MessageID
+	}
+	if messageID != "" {
+		s.remember(messageID, ctx, time.Now())
+	}
+}
+
+// failed ends tracing of the request which is not going to be responded.
+func (s *submitTracing) failed(p pdu.PDU, err error) {
+	if s == nil {
+		return
+	}
+
+	if ctx, found := s.take(p.GetSequenceNumber()); found {
+		s.settings.Tracer.EndSubmit(ctx, nil, err)
+	}
+}
+
+// abort ends tracing of all requests waiting for response.
+func (s *submitTracing) abort(err error) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	inflight := s.inflight
+	s.inflight = make(map[int32]context.Context)
+	s.mu.Unlock()
+
+	for _, ctx := range inflight {
+		s.settings.Tracer.EndSubmit(ctx, nil, err)
+	}
+}
+
+// delivered links delivery receipt with its traced request, if remembered.
+func (s *submitTracing) delivered(p pdu.PDU) {
+	if s == nil || s.settings.ReceiptTimeout <= 0 {
+		return
+	}
+
+	deliver, ok := p.(*pdu.DeliverSM)
+	if !ok || !deliver.IsDeliveryReceipt() {
+		return
+	}
+
+	receipt, err := deliver.ParseDeliveryReceipt()
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+
+	s.mu.Lock()
+	s.expire(now)
+	pending, found := s.receipts[receipt.MessageID]
+	if found {
+		delete(s.receipts, receipt.MessageID)
+	}
+	s.mu.Unlock()
+
+	if found {
+		s.settings.Tracer.DeliveryReceipt(pending.ctx, deliver, receipt)
+	}
+}
+
+func (s *submitTracing) remember(messageID string, ctx context.Context, now time.Time) {
+	expire := now.Add(s.settings.ReceiptTimeout)
+
+	s.mu.Lock()
+	s.expire(now)
+	s.receipts[messageID] = pendingReceipt{ctx: ctx, expire: expire}
+	s.queue = append(s.queue, receiptEntry{messageID: messageID, expire: expire})
+	if s.settings.MaxPendingReceipts > 0 {
+		for len(s.receipts) > s.settings.MaxPendingReceipts {
+			s.pop()
+		}
+	}
+	s.mu.Unlock()
+}
+
+// expire forgets message ids not receipted in time. Lock must be held.
+func (s *submitTracing) expire(now time.Time) {
+	for len(s.queue) > 0 && now.After(s.queue[0].expire) {
+		s.pop()
+	}
+}
+
+// pop forgets the oldest message id. Lock must be held.
+func (s *submitTracing) pop() {
+	entry := s.queue[0]
+	s.queue[0] = receiptEntry{}
+	s.queue = s.queue[1:]
+
+	// message id may have been remembered again later, or already receipted
+	if pending, found := s.receipts[entry.messageID]; found && pending.expire.Equal(entry.expire) {
+		delete(s.receipts, entry.messageID)
+	}
+}
@@ -0,0 +1,131 @@
+// Package opentelemetry traces gosmpp submitted requests with OpenTelemetry spans.
+//
+// It lives in its own module, so that the core module does not depend on OpenTelemetry.
+//
+//	session, err := gosmpp.NewSession(connector, gosmpp.Settings{
+//		SubmitTracing: &gosmpp.SubmitTracing{
+//			Tracer:         opentelemetry.New(),
+//			ReceiptTimeout: 24 * time.Hour,
+//		},
+//		...
+//	}, rebindingInterval)
+//
+//	resp, err := session.SubmitWithContext(ctx, submitSM) // span is a child of the span in ctx
+package opentelemetry
+
+import (
+	"context"
+
+	"github.com/linxGnu/gosmpp"
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/linxGnu/gosmpp/tracing/opentelemetry"
+
+// Attribute keys of the spans.
+const (
+	CommandIDKey      = attribute.Key("smpp.command_id")
+	SequenceNumberKey = attribute.Key("smpp.sequence_number")
+	CommandStatusKey  = attribute.Key("smpp.command_status")
+	MessageIDKey      = attribute.Key("smpp.message_id")
+	ReceiptStatKey    = attribute.Key("smpp.receipt.stat")
+	ReceiptErrKey     = attribute.Key("smpp.receipt.err")
+)
+
+// Tracer implements gosmpp.SubmitTracer.
+//
+// A client span is started on submit and ended on response. Delivery receipt is traced
+// with its own span, linked to the span of the submit.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+var _ gosmpp.SubmitTracer = (*Tracer)(nil)
+
+// Option configures Tracer.
+type Option func(*options)
+
+type options struct {
+	provider trace.TracerProvider
+}
+
+// WithTracerProvider sets TracerProvider creating spans. Global provider is used by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(o *options) {
+		o.provider = provider
+	}
+}
+
+// New creates Tracer.
+func New(opts ...Option) *Tracer {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.provider == nil {
+		o.provider = otel.GetTracerProvider()
+	}
+
+	return &Tracer{
+		tracer: o.provider.Tracer(instrumentationName),
+	}
+}
+
+// StartSubmit implements gosmpp.SubmitTracer.
+func (t *Tracer) StartSubmit(ctx context.Context, req pdu.PDU) context.Context {
+	header := req.GetHeader()
+	ctx, _ = t.tracer.Start(ctx, "smpp "+header.CommandID.String(),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			CommandIDKey.String(header.CommandID.String()),
+			SequenceNumberKey.Int64(int64(header.SequenceNumber)),
+		),
+	)
+	return ctx
+}
+
+// EndSubmit implements gosmpp.SubmitTracer.
+func (t *Tracer) EndSubmit(ctx context.Context, resp pdu.PDU, err error) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+
+	status := resp.GetHeader().CommandStatus
+	span.SetAttributes(CommandStatusKey.String(status.String()))
+
+	switch r := resp.(type) {
+	case *pdu.SubmitSMResp:
+		span.SetAttributes(MessageIDKey.String(r.MessageID))
+	case *pdu.DataSMResp:
+		span.SetAttributes(MessageIDKey.String(r.MessageID))
+	}
+
+	if status != data.ESME_ROK {
+		span.SetStatus(codes.Error, status.Desc())
+	}
+}
+
+// DeliveryReceipt implements gosmpp.SubmitTracer.
+func (t *Tracer) DeliveryReceipt(ctx context.Context, _ *pdu.DeliverSM, receipt pdu.DeliveryReceipt) {
+	_, span := t.tracer.Start(context.Background(), "smpp delivery_receipt",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithLinks(trace.Link{SpanContext: trace.SpanContextFromContext(ctx)}),
+		trace.WithAttributes(
+			MessageIDKey.String(receipt.MessageID),
+			ReceiptStatKey.String(receipt.Stat),
+			ReceiptErrKey.String(receipt.Err),
+		),
+	)
+	span.End()
+}
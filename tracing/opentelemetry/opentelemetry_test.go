@@ -0,0 +1,66 @@
+package opentelemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := New(WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+
+	parent, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "parent")
+	defer span.End()
+
+	// responded
+	sm := pdu.NewSubmitSM()
+	ctx := tracer.StartSubmit(parent, sm)
+	resp := sm.GetResponse().(*pdu.SubmitSMResp)
+	resp.MessageID = "msg-1"
+	tracer.EndSubmit(ctx, resp, nil)
+
+	// receipt
+	receipt := pdu.DeliveryReceipt{MessageID: "msg-1", Stat: "DELIVRD", Err: "000"}
+	tracer.DeliveryReceipt(ctx, pdu.NewDeliverSM().(*pdu.DeliverSM), receipt)
+
+	// rejected and failed
+	rejected := pdu.NewSubmitSM()
+	resp = rejected.GetResponse().(*pdu.SubmitSMResp)
+	resp.CommandStatus = data.ESME_RINVDSTADR
+	tracer.EndSubmit(tracer.StartSubmit(context.Background(), rejected), resp, nil)
+	tracer.EndSubmit(tracer.StartSubmit(context.Background(), pdu.NewDataSM()), nil, errors.New("expired"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+
+	submitted := spans[0]
+	require.Equal(t, "smpp SUBMIT_SM", submitted.Name())
+	require.Equal(t, trace.SpanKindClient, submitted.SpanKind())
+	require.Equal(t, span.SpanContext().TraceID(), submitted.SpanContext().TraceID())
+	require.Equal(t, span.SpanContext().SpanID(), submitted.Parent().SpanID())
+	require.Contains(t, submitted.Attributes(), MessageIDKey.String("msg-1"))
+	require.Contains(t, submitted.Attributes(), CommandStatusKey.String("ESME_ROK"))
+	require.Contains(t, submitted.Attributes(), SequenceNumberKey.Int64(int64(sm.GetSequenceNumber())))
+	require.Equal(t, codes.Unset, submitted.Status().Code)
+
+	delivered := spans[1]
+	require.Equal(t, "smpp delivery_receipt", delivered.Name())
+	require.Len(t, delivered.Links(), 1)
+	require.Equal(t, submitted.SpanContext(), delivered.Links()[0].SpanContext)
+	require.Contains(t, delivered.Attributes(), ReceiptStatKey.String("DELIVRD"))
+
+	require.Equal(t, codes.Error, spans[2].Status().Code)
+	require.Equal(t, codes.Error, spans[3].Status().Code)
+	require.Equal(t, "smpp DATA_SM", spans[3].Name())
+	require.Len(t, spans[3].Events(), 1) // recorded error
+}
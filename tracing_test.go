@@ -0,0 +1,209 @@
+package gosmpp
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/data"
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+type parentKey struct{}
+
+type spanKey struct{}
+
+type tracedEnd struct {
+	ctx  context.Context
+	resp pdu.PDU
+	err  error
+}
+
+type tracedReceipt struct {
+	ctx     context.Context
+	receipt pdu.DeliveryReceipt
+}
+
+type recordingTracer struct {
+	ended    chan tracedEnd
+	receipts chan tracedReceipt
+}
+
+func newRecordingTracer() *recordingTracer {
+	return &recordingTracer{
+		ended:    make(chan tracedEnd, 8),
+		receipts: make(chan tracedReceipt, 8),
+	}
+}
+
+func (r *recordingTracer) StartSubmit(ctx context.Context, req pdu.PDU) context.Context {
+	return context.WithValue(ctx, spanKey{}, req.GetSequenceNumber())
+}
+
+func (r *recordingTracer) EndSubmit(ctx context.Context, resp pdu.PDU, err error) {
+	r.ended <- tracedEnd{ctx: ctx, resp: resp, err: err}
+}
+
+func (r *recordingTracer) DeliveryReceipt(ctx context.Context, _ *pdu.DeliverSM, receipt pdu.DeliveryReceipt) {
+	r.receipts <- tracedReceipt{ctx: ctx, receipt: receipt}
+}
+
+func newReceiptPDU(t *testing.T, messageID string) *pdu.DeliverSM {
+	p := pdu.NewDeliverSM().(*pdu.DeliverSM)
+	p.EsmClass = data.SM_SMSC_DLV_RCPT_TYPE
+	require.NoError(t, p.Message.SetMessageWithEncoding("id:"+messageID+" stat:DELIVRD", data.GSM7BIT))
+	return p
+}
+
+func TestSubmitTracing(t *testing.T) {
+	t.Run("Receipt", func(t *testing.T) {
+		client, server := net.Pipe()
+		go pipeSMSC(server, func(p pdu.PDU) pdu.PDU {
+			if sm, ok := p.(*pdu.SubmitSM); ok {
+				resp := sm.GetResponse().(*pdu.SubmitSMResp)
+				resp.MessageID = "msg-1"
+				return resp
+			}
+			return nil
+		})
+		peer := NewConnection(server)
+
+		tracer := newRecordingTracer()
+		responses := make(chan Response, 1)
+		trans := newTransceivable(NewConnection(client), Settings{
+			ReadTimeout: 2 * time.Second,
+			WindowedRequestTracking: &WindowedRequestTracking{
+				OnExpectedPduResponse: func(r Response) {
+					responses <- r
+				},
+				OnReceivedPduRequest: func(p pdu.PDU) (pdu.PDU, bool) {
+					return p.GetResponse(), false
+				},
+				MaxWindowSize:      2,
+				StoreAccessTimeOut: 100,
+			},
+			SubmitTracing: &SubmitTracing{
+				Tracer:         tracer,
+				ReceiptTimeout: time.Minute,
+			},
+		}, NewDefaultStore())
+		trans.start()
+		defer func() {
+			_ = trans.Close()
+		}()
+
+		// waiting submitter
+		sm := pdu.NewSubmitSM()
+		ctx := context.WithValue(context.Background(), parentKey{}, "parent")
+		resp, err := trans.SubmitWithContext(ctx, sm)
+		require.NoError(t, err)
+
+		ended := <-tracer.ended
+		require.Equal(t, resp, ended.resp)
+		require.NoError(t, ended.err)
+		require.Equal(t, "parent", ended.ctx.Value(parentKey{}))
+		require.Equal(t, sm.GetSequenceNumber(), ended.ctx.Value(spanKey{}))
+		require.Equal(t, sm.GetSequenceNumber(), (<-responses).OriginalRequest.GetSequenceNumber())
+
+		// the request in window carries the traced context
+		sm = pdu.NewSubmitSM()
+		require.NoError(t, trans.Submit(sm))
+		response := <-responses
+		require.Equal(t, sm.GetSequenceNumber(), response.OriginalRequest.Context.Value(spanKey{}))
+		require.Equal(t, sm.GetSequenceNumber(), (<-tracer.ended).ctx.Value(spanKey{}))
+
+		// message id is reused by SMSC, the latest request gets the receipt
+		_, err = peer.WritePDU(newReceiptPDU(t, "msg-1"))
+		require.NoError(t, err)
+		receipt := <-tracer.receipts
+		require.Equal(t, "msg-1", receipt.receipt.MessageID)
+		require.Equal(t, sm.GetSequenceNumber(), receipt.ctx.Value(spanKey{}))
+
+		// receipt is linked only once
+		_, err = peer.WritePDU(newReceiptPDU(t, "msg-1"))
+		require.NoError(t, err)
+		select {
+		case <-tracer.receipts:
+			t.Fatal("receipt must not be linked twice")
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		client, server := net.Pipe()
+		go pipeSMSC(server, func(pdu.PDU) pdu.PDU {
+			return nil
+		})
+
+		tracer := newRecordingTracer()
+		trans := newTransceivable(NewConnection(client), Settings{
+			ReadTimeout: 2 * time.Second,
+			WindowedRequestTracking: &WindowedRequestTracking{
+				MaxWindowSize:      2,
+				PduExpireTimeOut:   50 * time.Millisecond,
+				ExpireCheckTimer:   20 * time.Millisecond,
+				StoreAccessTimeOut: 100,
+			},
+			SubmitTracing: &SubmitTracing{
+				Tracer: tracer,
+			},
+		}, NewDefaultStore())
+		trans.start()
+
+		// enquire_link is not traced
+		require.NoError(t, trans.Submit(pdu.NewEnquireLink()))
+
+		sm := pdu.NewSubmitSM()
+		require.NoError(t, trans.Submit(sm))
+		ended := <-tracer.ended
+		require.Equal(t, ErrRequestExpired, ended.err)
+		require.Nil(t, ended.resp)
+		require.Equal(t, sm.GetSequenceNumber(), ended.ctx.Value(spanKey{}))
+
+		// waiting submitter gives up, but the request is traced until the bind is closed
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := trans.SubmitWithContext(ctx, pdu.NewDataSM())
+		require.Equal(t, context.DeadlineExceeded, err)
+
+		require.NoError(t, trans.Close())
+		require.Equal(t, ErrConnectionClosing, (<-tracer.ended).err)
+	})
+}
+
+func TestSubmitTracingReceipts(t *testing.T) {
+	s := newSubmitTracing(&SubmitTracing{
+		Tracer:             newRecordingTracer(),
+		ReceiptTimeout:     time.Minute,
+		MaxPendingReceipts: 2,
+	})
+
+	now := time.Now()
+	s.remember("a", context.Background(), now.Add(-2*time.Minute))
+	require.Contains(t, s.receipts, "a")
+
+	// expired one is forgotten
+	s.remember("b", context.Background(), now)
+	require.NotContains(t, s.receipts, "a")
+	s.remember("c", context.Background(), now)
+	require.Len(t, s.receipts, 2)
+
+	// limit drops the oldest
+	s.remember("d", context.Background(), now)
+	require.NotContains(t, s.receipts, "b")
+	require.Contains(t, s.receipts, "c")
+	require.Contains(t, s.receipts, "d")
+
+	// remembered again, the stale entry must not forget it
+	s.remember("c", context.Background(), now.Add(time.Second))
+	s.remember("e", context.Background(), now.Add(time.Second))
+	require.Contains(t, s.receipts, "c")
+	require.Contains(t, s.receipts, "e")
+	require.Len(t, s.receipts, 2)
+
+	require.Nil(t, newSubmitTracing(nil))
+	require.Nil(t, newSubmitTracing(&SubmitTracing{}))
+}
@@ -11,6 +11,10 @@ import (
 
 var (
 	ErrWindowNotConfigured = errors.New("window settings not configured")
+	// ErrNoResponseExpected indicates the submitted PDU has no response to wait for.
+	ErrNoResponseExpected = errors.New("PDU does not expect any response")
+	// ErrRequestExpired indicates the request expired in the window without response.
+	ErrRequestExpired = errors.New("request expired without response")
 )
 
 type transceivable struct {
@@ -25,15 +29,32 @@ type transceivable struct {
 
 	aliveState   int32
 	requestStore RequestStore
+	pending      *pendingResponses
 }
 type TransceivableOption func(session *Session)
 
 func newTransceivable(conn *Connection, settings Settings, requestStore RequestStore) *transceivable {
+	if settings.ConcatReassembly != nil && settings.reassembler == nil {
+		settings.reassembler = newReassembler(*settings.ConcatReassembly)
+	}
+	if settings.WindowedRequestTracking != nil && settings.BlockOnWindowFull && settings.MaxWindowSize > 0 && requestStore != nil {
+		settings.window = newWindowSlots(requestStore, settings.WindowedRequestTracking)
+	}
+	if settings.metrics == nil {
+		settings.metrics = newMetricsRecorder(settings.Metrics)
+	}
+	if settings.ThrottleControl != nil && settings.throttler == nil {
+		settings.throttler = newThrottler(*settings.ThrottleControl)
+	}
+	if settings.tracing == nil {
+		settings.tracing = newSubmitTracing(settings.SubmitTracing)
+	}
 
 	t := &transceivable{
 		settings:     settings,
 		conn:         conn,
 		requestStore: requestStore,
+		pending:      newPendingResponses(),
 	}
 	t.ctx, t.cancel = context.WithCancel(context.Background())
 
@@ -42,11 +63,37 @@ func newTransceivable(conn *Connection, settings Settings, requestStore RequestS
 
 		EnquireLink: settings.EnquireLink,
 
-		OnSubmitError: settings.OnSubmitError,
+		SequenceGenerator: settings.SequenceGenerator,
+
+		RateLimiter: settings.RateLimiter,
+
+		OnRateLimitWait: settings.OnRateLimitWait,
+
+		throttler: settings.throttler,
+
+		window: settings.window,
+
+		metrics: settings.metrics,
+
+		tracing: settings.tracing,
+
+		OnSubmitError: func(p pdu.PDU, err error) {
+			if settings.throttler != nil {
+				settings.throttler.forget(p.GetSequenceNumber())
+			}
+			settings.tracing.failed(p, err)
+			t.pending.resolve(p.GetSequenceNumber(), nil, err)
+
+			if settings.OnSubmitError != nil {
+				settings.OnSubmitError(p, err)
+			}
+		},
 
 		OnClosed: func(state State) {
 			switch state {
 			case ConnectionIssue:
+				t.closePending()
+
 				// also close input
 				_ = t.in.close(ExplicitClosing)
 
@@ -73,6 +120,8 @@ func newTransceivable(conn *Connection, settings Settings, requestStore RequestS
 		OnClosed: func(state State) {
 			switch state {
 			case InvalidStreaming, UnbindClosing:
+				t.closePending()
+
 				// also close output
 				_ = t.out.close(ExplicitClosing)
 
@@ -89,6 +138,22 @@ func newTransceivable(conn *Connection, settings Settings, requestStore RequestS
 		response: func(p pdu.PDU) {
 			_ = t.Submit(p)
 		},
+
+		onResponse: t.resolve,
+
+		ConcatReassembly: settings.ConcatReassembly,
+
+		InboundWorkers: settings.InboundWorkers,
+
+		reassembler: settings.reassembler,
+
+		window: settings.window,
+
+		metrics: settings.metrics,
+
+		tracing: settings.tracing,
+
+		rejectBind: settings.rejectBind,
 	},
 		requestStore,
 	)
@@ -123,6 +188,98 @@ func (t *transceivable) Submit(p pdu.PDU) error {
 	return t.out.Submit(p)
 }
 
+// SubmitWithContext submits a PDU and waits for its response.
+//
+// The response is matched with the request by sequence number. Waiting is aborted
+// once ctx is done, SMSC answers with generic_nack, the request expires in the window
+// or the bind is lost.
+func (t *transceivable) SubmitWithContext(ctx context.Context, p pdu.PDU) (resp pdu.PDU, err error) {
+	if p == nil || !p.CanResponse() {
+		return nil, ErrNoResponseExpected
+	}
+
+	// waiter is registered once sequence number is known, before the request is written
+	var ch chan pendingResult
+	register := func(err error) error {
+		if err == nil {
+			ch, err = t.pending.add(p.GetSequenceNumber())
+		}
+		return err
+	}
+	defer func() {
+		if ch != nil {
+			t.pending.remove(p.GetSequenceNumber())
+		}
+	}()
+
+	if t.settings.SequenceGenerator == nil {
+		if err = register(nil); err == nil {
+			err = t.out.submit(ctx, p)
+		}
+	} else {
+		// numbered by the writer, in the same order as Submit
+		registered := make(chan error, 1)
+		if err = t.out.enqueue(ctx, p, func(err error) error {
+			err = register(err)
+			registered <- err
+			return err
+		}); err == nil {
+			err = <-registered
+		}
+	}
+	if err != nil {
+		return
+	}
+
+	select {
+	case r := <-ch:
+		return r.resp, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// closePending fails all requests waiting for response.
+func (t *transceivable) closePending() {
+	t.pending.close(ErrConnectionClosing)
+	t.settings.tracing.abort(ErrConnectionClosing)
+}
+
+// resolve hands an incoming response to its waiting submitter, if any,
+// or re-queues the throttled request.
+func (t *transceivable) resolve(p pdu.PDU) (handled, requeued bool) {
+	if !isResponse(p) {
+		return
+	}
+
+	if t.settings.throttler != nil {
+		if req := t.settings.throttler.handle(p); req != nil {
+			go t.requeue(req)
+			return true, true
+		}
+	}
+
+	t.settings.tracing.responded(p)
+
+	var err error
+	if p.IsGNack() {
+		err = GenericNackError{CommandStatus: p.GetHeader().CommandStatus}
+	}
+	return t.pending.resolve(p.GetSequenceNumber(), p, err), false
+}
+
+// requeue submits throttled request again, once backoff is over.
+func (t *transceivable) requeue(p pdu.PDU) {
+	if err := t.out.submit(context.Background(), p); err != nil {
+		t.settings.throttler.forget(p.GetSequenceNumber())
+		t.pending.resolve(p.GetSequenceNumber(), nil, err)
+
+		if t.settings.OnSubmitError != nil {
+			t.settings.OnSubmitError(p, err)
+		}
+	}
+}
+
 func (t *transceivable) GetWindowSize() (int, error) {
 	if t.settings.WindowedRequestTracking != nil {
 		ctx, cancelFunc := context.WithTimeout(context.Background(), t.settings.StoreAccessTimeOut*time.Millisecond)
@@ -145,6 +302,12 @@ func (t *transceivable) windowCleanup() {
 			for _, request := range t.requestStore.List(ctx) {
 				if time.Since(request.TimeSent) > t.settings.PduExpireTimeOut {
 					_ = t.requestStore.Delete(ctx, request.GetSequenceNumber())
+					if t.settings.window != nil {
+						t.settings.window.notify()
+					}
+					t.settings.metrics.expired(request.PDU)
+					t.settings.tracing.failed(request.PDU, ErrRequestExpired)
+					t.pending.resolve(request.GetSequenceNumber(), nil, ErrRequestExpired)
 					if t.settings.OnExpiredPduRequest != nil {
 						if t.settings.OnExpiredPduRequest(request.PDU) {
 							_ = t.closing(ConnectionIssue)
@@ -153,6 +316,8 @@ func (t *transceivable) windowCleanup() {
 				}
 			}
 			cancelFunc() //defer should not be used because we are inside loop
+
+			t.settings.metrics.window(t.requestStore, t.settings.StoreAccessTimeOut*time.Millisecond)
 		}
 	}
 }
@@ -161,6 +326,9 @@ func (t *transceivable) closing(state State) (err error) {
 	if atomic.CompareAndSwapInt32(&t.aliveState, Alive, Closed) {
 		t.cancel()
 
+		// fail all submitters waiting for response
+		t.closePending()
+
 		// closing input and output
 		_ = t.out.close(StoppingProcessOnly)
 		_ = t.in.close(StoppingProcessOnly)
@@ -9,6 +9,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/linxGnu/gosmpp/data"
 	"github.com/linxGnu/gosmpp/pdu"
 )
 
@@ -18,11 +19,22 @@ var (
 	ErrWindowsFull       = errors.New("window full")
 )
 
+// outgoing is a PDU queued for the writer.
+type outgoing struct {
+	p pdu.PDU
+
+	// numbered is called by the writer once sequence number is assigned from SequenceGenerator,
+	// right before writing, or with the error dropping the PDU unwritten. PDU is dropped
+	// if it returns error. Nil for PDU keeping its sequence number.
+	numbered func(error) error
+}
+
 type transmittable struct {
 	settings Settings
 
 	wg    sync.WaitGroup
-	input chan pdu.PDU
+	input chan outgoing
+	done  chan struct{}
 
 	conn *Connection
 
@@ -35,7 +47,8 @@ func newTransmittable(conn *Connection, settings Settings, requestStore RequestS
 	t := &transmittable{
 		settings:     settings,
 		conn:         conn,
-		input:        make(chan pdu.PDU, 1),
+		input:        make(chan outgoing, 1),
+		done:         make(chan struct{}),
 		aliveState:   Alive,
 		pendingWrite: 0,
 		requestStore: requestStore,
@@ -46,6 +59,9 @@ func newTransmittable(conn *Connection, settings Settings, requestStore RequestS
 
 func (t *transmittable) close(state State) (err error) {
 	if atomic.CompareAndSwapInt32(&t.aliveState, Alive, Closed) {
+		// abort submits waiting for rate limiter
+		close(t.done)
+
 		for atomic.LoadInt32(&t.pendingWrite) != 0 {
 			runtime.Gosched()
 		}
@@ -57,7 +73,9 @@ func (t *transmittable) close(state State) (err error) {
 		t.wg.Wait()
 
 		// try to send unbind
-		_, _ = t.write(pdu.NewUnbind())
+		unbind := pdu.NewUnbind()
+		t.assignSequenceNumber(unbind)
+		_, _ = t.write(unbind)
 
 		// close connection
 		if state != StoppingProcessOnly {
@@ -103,11 +121,91 @@ func (t *transmittable) closing(state State) {
 }
 
 // Submit a PDU.
+//
+// Sequence number is assigned from SequenceGenerator, if any, by the writer so that sequence numbers
+// go on the wire in order. Submit returns once it is assigned.
 func (t *transmittable) Submit(p pdu.PDU) (err error) {
+	if t.settings.SequenceGenerator == nil || p == nil || !p.CanResponse() {
+		return t.enqueue(context.Background(), p, nil)
+	}
+
+	numbered := make(chan error, 1)
+	if err = t.enqueue(context.Background(), p, func(err error) error {
+		numbered <- err
+		return err
+	}); err == nil {
+		err = <-numbered
+	}
+	return
+}
+
+// assignSequenceNumber assigns sequence number to request PDU from SequenceGenerator, if any.
+func (t *transmittable) assignSequenceNumber(p pdu.PDU) {
+	if t.settings.SequenceGenerator != nil && p != nil && p.CanResponse() {
+		p.SetSequenceNumber(t.settings.SequenceGenerator.Next())
+	}
+}
+
+// submit a PDU keeping its sequence number, waiting for rate limiter if PDU is billable.
+func (t *transmittable) submit(ctx context.Context, p pdu.PDU) (err error) {
+	return t.enqueue(ctx, p, nil)
+}
+
+// enqueue queues PDU for the writer. Unless sequence number is assigned by the writer
+// calling `numbered`, the PDU is traced and tracked for re-queueing right away.
+func (t *transmittable) enqueue(ctx context.Context, p pdu.PDU, numbered func(error) error) (err error) {
+	assign := numbered != nil
+
+	if t.conn != nil && t.conn.interfaceVersion == data.SMPP_V33_WIRE {
+		if err = checkSMPP33(p); err != nil {
+			return
+		}
+	}
+
+	if !assign {
+		t.settings.tracing.start(ctx, p)
+		defer func() {
+			if err != nil {
+				t.settings.tracing.failed(p, err)
+			}
+		}()
+	}
+
+	if t.settings.throttler != nil && isBillable(p) {
+		if err = t.settings.throttler.wait(ctx, t.done); err != nil {
+			return
+		}
+		if !assign {
+			t.settings.throttler.track(p)
+		}
+	}
+
+	window := t.settings.window
+	if window != nil && isAllowPDU(p) {
+		if err = window.acquire(ctx, t.done); err != nil {
+			return
+		}
+		defer func() {
+			if err != nil {
+				window.release(true)
+			}
+		}()
+	}
+
+	if t.settings.RateLimiter != nil && isBillable(p) {
+		var waited time.Duration
+		if waited, err = t.settings.RateLimiter.wait(ctx, t.done); err != nil {
+			return
+		}
+		if waited > 0 && t.settings.OnRateLimitWait != nil {
+			t.settings.OnRateLimitWait(p, waited)
+		}
+	}
+
 	atomic.AddInt32(&t.pendingWrite, 1)
 
 	if atomic.LoadInt32(&t.aliveState) == Alive {
-		t.input <- p
+		t.input <- outgoing{p: p, numbered: numbered}
 	} else {
 		err = ErrConnectionClosing
 	}
@@ -132,16 +230,20 @@ func (t *transmittable) start() {
 }
 
 func (t *transmittable) drain() {
-	for range t.input {
+	for o := range t.input {
+		if o.numbered != nil {
+			_ = o.numbered(ErrConnectionClosing)
+		}
 	}
 }
 
 func (t *transmittable) loop() {
 	defer t.drain()
 
-	for p := range t.input {
-		if p != nil {
+	for o := range t.input {
+		if p := t.prepare(o); p != nil {
 			n, err := t.write(p)
+			t.releaseWindow(p, err)
 			if t.check(p, n, err) {
 				return
 			}
@@ -159,19 +261,19 @@ func (t *transmittable) loopWithEnquireLink() {
 	for {
 		select {
 		case <-ticker.C:
-			eqp := pdu.NewEnquireLink()
-			n, err := t.write(eqp)
+			eqp, n, err := t.enquireLink()
 			if t.check(eqp, n, err) {
 				return
 			}
 
-		case p, ok := <-t.input:
+		case o, ok := <-t.input:
 			if !ok {
 				return
 			}
 
-			if p != nil {
+			if p := t.prepare(o); p != nil {
 				n, err := t.write(p)
+				t.releaseWindow(p, err)
 				if t.check(p, n, err) {
 					return
 				}
@@ -180,6 +282,50 @@ func (t *transmittable) loopWithEnquireLink() {
 	}
 }
 
+// enquireLink writes enquire_link. It takes a window slot like submitted requests,
+// so that it never pushes out a request already admitted into the window.
+func (t *transmittable) enquireLink() (p pdu.PDU, n int, err error) {
+	p = pdu.NewEnquireLink()
+	t.assignSequenceNumber(p)
+
+	if t.settings.window != nil {
+		if !t.settings.window.tryAcquire() {
+			return p, 0, ErrWindowsFull
+		}
+		defer func() {
+			t.releaseWindow(p, err)
+		}()
+	}
+
+	n, err = t.write(p)
+	return
+}
+
+// prepare assigns sequence number to queued PDU right before writing, then traces
+// and tracks it for re-queueing as done by submit for PDU numbered upfront.
+// Nil is returned if the PDU is dropped.
+func (t *transmittable) prepare(o outgoing) pdu.PDU {
+	if o.numbered != nil {
+		t.assignSequenceNumber(o.p)
+		if err := o.numbered(nil); err != nil {
+			t.releaseWindow(o.p, err)
+			return nil
+		}
+		t.settings.tracing.start(context.Background(), o.p)
+		if t.settings.throttler != nil && isBillable(o.p) {
+			t.settings.throttler.track(o.p)
+		}
+	}
+	return o.p
+}
+
+// releaseWindow releases window slot reserved by submit, once the request is written or dropped.
+func (t *transmittable) releaseWindow(p pdu.PDU, err error) {
+	if t.settings.window != nil && isAllowPDU(p) {
+		t.settings.window.release(err != nil)
+	}
+}
+
 // check error and do closing if need
 func (t *transmittable) check(p pdu.PDU, n int, err error) (closing bool) {
 	if err == nil {
@@ -218,6 +364,8 @@ func (t *transmittable) write(p pdu.PDU) (n int, err error) {
 		return
 	}
 
+	t.settings.metrics.sending(p)
+
 	if t.settings.WindowedRequestTracking != nil && t.settings.MaxWindowSize > 0 && isAllowPDU(p) {
 		ctx, cancelFunc := context.WithTimeout(context.Background(), t.settings.StoreAccessTimeOut*time.Millisecond)
 		defer cancelFunc()
@@ -227,18 +375,23 @@ func (t *transmittable) write(p pdu.PDU) (n int, err error) {
 			return 0, err
 		}
 		if length < int(t.settings.MaxWindowSize) {
-			n, err = t.conn.WritePDU(p)
-			if err != nil {
-				return 0, err
-			}
+			// track request before writing, so that a fast response always finds it
 			request := Request{
 				PDU:      p,
 				TimeSent: time.Now(),
+				Context:  t.settings.tracing.context(p),
 			}
 			err = t.requestStore.Set(ctx, request)
 			if err != nil {
 				return 0, err
 			}
+			t.settings.metrics.windowSize(length + 1)
+
+			n, err = t.conn.WritePDU(p)
+			if err != nil {
+				_ = t.requestStore.Delete(ctx, p.GetSequenceNumber())
+				return 0, err
+			}
 		} else {
 			return 0, ErrWindowsFull
 		}
@@ -246,6 +399,9 @@ func (t *transmittable) write(p pdu.PDU) (n int, err error) {
 		n, err = t.conn.WritePDU(p)
 	}
 
+	if err == nil {
+		t.settings.metrics.sent(p)
+	}
 	return
 }
 
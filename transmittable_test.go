@@ -1,6 +1,7 @@
 package gosmpp
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"sync"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/linxGnu/gosmpp/pdu"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -60,7 +62,7 @@ func TestTransmit(t *testing.T) {
 		require.NoError(t, err)
 
 		var tr transmittable
-		tr.input = make(chan pdu.PDU, 1)
+		tr.input = make(chan outgoing, 1)
 
 		c := NewConnection(conn)
 		defer func() {
@@ -107,7 +109,7 @@ func TestTransmit(t *testing.T) {
 
 	t.Run("SubmitErr", func(t *testing.T) {
 		var tr transmittable
-		tr.input = make(chan pdu.PDU, 1)
+		tr.input = make(chan outgoing, 1)
 
 		tr.aliveState = 1
 		err := tr.Submit(nil)
@@ -179,3 +181,107 @@ func TestConcurrentSubmitClose(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestSequenceGenerator(t *testing.T) {
+	client, server := net.Pipe()
+
+	received := make(chan pdu.PDU, 4)
+	go pipeSMSC(server, func(p pdu.PDU) pdu.PDU {
+		received <- p
+		if p.CanResponse() {
+			return p.GetResponse()
+		}
+		return nil
+	})
+
+	generator := pdu.NewAtomicSequenceGenerator(100)
+	trans := newTransceivable(NewConnection(client), Settings{
+		ReadTimeout:       2 * time.Second,
+		SequenceGenerator: generator,
+		OnPDU:             func(pdu.PDU, bool) {},
+	}, nil)
+	trans.start()
+	defer func() {
+		_ = trans.Close()
+	}()
+
+	// assigned on submit, not on construction
+	p := pdu.NewSubmitSM()
+	require.NoError(t, trans.Submit(p))
+	require.EqualValues(t, 101, (<-received).GetSequenceNumber())
+
+	resp, err := trans.SubmitWithContext(context.Background(), pdu.NewSubmitSM())
+	require.NoError(t, err)
+	require.EqualValues(t, 102, resp.GetSequenceNumber())
+	require.EqualValues(t, 102, (<-received).GetSequenceNumber())
+
+	// responses keep sequence number of their request
+	deliver := pdu.NewDeliverSM()
+	deliver.SetSequenceNumber(7)
+	_, err = NewConnection(server).WritePDU(deliver)
+	require.NoError(t, err)
+
+	deliverResp := <-received
+	require.IsType(t, &pdu.DeliverSMResp{}, deliverResp)
+	require.EqualValues(t, 7, deliverResp.GetSequenceNumber())
+	require.EqualValues(t, 102, generator.Last())
+}
+
+func TestSequenceGeneratorWireOrder(t *testing.T) {
+	client, server := net.Pipe()
+
+	received := make(chan int32, 20)
+	go pipeSMSC(server, func(p pdu.PDU) pdu.PDU {
+		if _, ok := p.(*pdu.SubmitSM); ok {
+			received <- p.GetSequenceNumber()
+			return p.GetResponse()
+		}
+		return nil
+	})
+
+	trans := newTransceivable(NewConnection(client), Settings{
+		ReadTimeout:       2 * time.Second,
+		SequenceGenerator: pdu.NewAtomicSequenceGenerator(0),
+		RateLimiter:       NewRateLimiter(500, 1),
+	}, nil)
+	trans.start()
+	defer func() {
+		_ = trans.Close()
+	}()
+
+	// submitters delayed by rate limiter in any order, waiting for response or not
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		assigned = make(map[int32]bool)
+	)
+	for i := 0; i < cap(received); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			sm := pdu.NewSubmitSM()
+			if i%2 == 0 {
+				assert.NoError(t, trans.Submit(sm))
+			} else {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				resp, err := trans.SubmitWithContext(ctx, sm)
+				if assert.NoError(t, err) {
+					assert.Equal(t, sm.GetSequenceNumber(), resp.GetSequenceNumber())
+				}
+			}
+
+			// number is known once submitted
+			mu.Lock()
+			assigned[sm.GetSequenceNumber()] = true
+			mu.Unlock()
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 1; i <= cap(received); i++ {
+		require.EqualValues(t, i, <-received)
+		require.True(t, assigned[int32(i)])
+	}
+}
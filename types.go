@@ -1,6 +1,8 @@
 package gosmpp
 
 import (
+	"time"
+
 	"github.com/linxGnu/gosmpp/pdu"
 )
 
@@ -16,6 +18,15 @@ type AllPDUCallback func(pdu pdu.PDU) (responsePdu pdu.PDU, closeBind bool)
 // PDUErrorCallback notifies fail-to-submit PDU with along error.
 type PDUErrorCallback func(pdu pdu.PDU, err error)
 
+// RateLimitWaitCallback notifies PDU delayed by RateLimiter along with wait time.
+type RateLimitWaitCallback func(pdu pdu.PDU, wait time.Duration)
+
+// ThrottledCallback notifies response with ESME_RTHROTTLED or ESME_RMSGQFUL status.
+//
+// The request is nil if it is not tracked for re-queueing. `Retries` is the number of
+// retries already made and `requeued` indicates the request is sent again.
+type ThrottledCallback func(req pdu.PDU, resp pdu.PDU, retries int, requeued bool)
+
 // ErrorCallback notifies happened error while reading PDU.
 type ErrorCallback func(error)
 
@@ -24,3 +35,9 @@ type ClosedCallback func(State)
 
 // RebindCallback notifies rebind event due to State.
 type RebindCallback func()
+
+// RebindEndpointCallback notifies rebind event along with the SMSC endpoint bound.
+type RebindEndpointCallback func(endpoint string)
+
+// InboundKeyFunc returns the key of received request, requests with the same key are handled in order.
+type InboundKeyFunc func(pdu pdu.PDU) string
@@ -0,0 +1,105 @@
+package gosmpp
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// windowPollInterval bounds waiting for a free window slot, in case
+// requests are removed from the store outside of the library.
+const windowPollInterval = 100 * time.Millisecond
+
+// windowSlots admits requests into the window, blocking while it is full.
+//
+// Requests admitted but not yet written are reserved, so that concurrent
+// submitters never overfill the window.
+type windowSlots struct {
+	store   RequestStore
+	size    int
+	timeout time.Duration
+
+	mu       sync.Mutex
+	reserved int
+	freed    chan struct{}
+}
+
+func newWindowSlots(store RequestStore, settings *WindowedRequestTracking) *windowSlots {
+	return &windowSlots{
+		store:   store,
+		size:    int(settings.MaxWindowSize),
+		timeout: settings.StoreAccessTimeOut * time.Millisecond,
+		freed:   make(chan struct{}),
+	}
+}
+
+// acquire reserves a window slot, waiting until one is freed, ctx is done or abort is closed.
+func (w *windowSlots) acquire(ctx context.Context, abort <-chan struct{}) error {
+	for {
+		admitted, freed, err := w.reserve(ctx)
+		if err != nil {
+			return err
+		}
+		if admitted {
+			return nil
+		}
+
+		timer := time.NewTimer(windowPollInterval)
+		select {
+		case <-freed:
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-abort:
+			timer.Stop()
+			return ErrConnectionClosing
+		}
+		timer.Stop()
+	}
+}
+
+// tryAcquire reserves a window slot if one is free, without waiting.
+func (w *windowSlots) tryAcquire() bool {
+	admitted, _, _ := w.reserve(context.Background())
+	return admitted
+}
+
+// reserve takes a slot if window is not full. Freed is closed once a slot might be available again.
+func (w *windowSlots) reserve(ctx context.Context) (admitted bool, freed chan struct{}, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	freed = w.freed
+
+	storeCtx, cancelFunc := context.WithTimeout(ctx, w.timeout)
+	length, err := w.store.Length(storeCtx)
+	cancelFunc()
+
+	if admitted = err == nil && length+w.reserved < w.size; admitted {
+		w.reserved++
+	}
+	return
+}
+
+// release gives back reserved slot, once the request is put into the store or dropped.
+func (w *windowSlots) release(dropped bool) {
+	w.mu.Lock()
+	w.reserved--
+	if dropped {
+		w.broadcast()
+	}
+	w.mu.Unlock()
+}
+
+// notify wakes up submitters waiting for a slot, after requests are removed from the store.
+func (w *windowSlots) notify() {
+	w.mu.Lock()
+	w.broadcast()
+	w.mu.Unlock()
+}
+
+// broadcast wakes up all waiters. Lock must be held.
+func (w *windowSlots) broadcast() {
+	close(w.freed)
+	w.freed = make(chan struct{})
+}
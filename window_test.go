@@ -0,0 +1,118 @@
+package gosmpp
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/linxGnu/gosmpp/pdu"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockOnWindowFull(t *testing.T) {
+	client, server := net.Pipe()
+
+	received := make(chan pdu.PDU, 8)
+	go pipeSMSC(server, func(p pdu.PDU) pdu.PDU {
+		received <- p
+		return nil
+	})
+	peer := NewConnection(server)
+
+	responses := make(chan Response, 8)
+	trans := newTransceivable(NewConnection(client), Settings{
+		ReadTimeout: 2 * time.Second,
+		WindowedRequestTracking: &WindowedRequestTracking{
+			OnExpectedPduResponse: func(r Response) {
+				responses <- r
+			},
+			MaxWindowSize:      2,
+			BlockOnWindowFull:  true,
+			StoreAccessTimeOut: 100,
+		},
+	}, NewDefaultStore())
+	trans.start()
+	defer func() {
+		_ = trans.Close()
+	}()
+
+	first := pdu.NewSubmitSM()
+	require.NoError(t, trans.Submit(first))
+	require.NoError(t, trans.Submit(pdu.NewSubmitSM()))
+	<-received
+	<-received
+
+	// window is full, waiting gives up on context deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := trans.SubmitWithContext(ctx, pdu.NewSubmitSM())
+	require.Equal(t, context.DeadlineExceeded, err)
+
+	// blocked until a response frees a slot
+	submitted := make(chan error, 1)
+	third := pdu.NewSubmitSM()
+	go func() {
+		submitted <- trans.Submit(third)
+	}()
+
+	select {
+	case <-submitted:
+		t.Fatal("submit must block while window is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	_, err = peer.WritePDU(first.GetResponse())
+	require.NoError(t, err)
+	require.Equal(t, first.GetSequenceNumber(), (<-responses).OriginalRequest.GetSequenceNumber())
+
+	require.NoError(t, <-submitted)
+	require.Equal(t, third.GetSequenceNumber(), (<-received).GetSequenceNumber())
+
+	size, err := trans.GetWindowSize()
+	require.NoError(t, err)
+	require.Equal(t, 2, size)
+}
+
+func TestWindowEnquireLink(t *testing.T) {
+	client, server := net.Pipe()
+	go pipeSMSC(server, func(pdu.PDU) pdu.PDU {
+		return nil
+	})
+
+	store := NewDefaultStore()
+	trans := newTransceivable(NewConnection(client), Settings{
+		ReadTimeout: 2 * time.Second,
+		WindowedRequestTracking: &WindowedRequestTracking{
+			MaxWindowSize:      1,
+			BlockOnWindowFull:  true,
+			StoreAccessTimeOut: 100,
+		},
+	}, store)
+	trans.start()
+	defer func() {
+		_ = trans.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// slot is admitted to submit_sm, enquire_link does not take it
+	require.NoError(t, trans.out.settings.window.acquire(ctx, nil))
+	_, _, err := trans.out.enquireLink()
+	require.Equal(t, ErrWindowsFull, err)
+
+	sm := pdu.NewSubmitSM()
+	_, err = trans.out.write(sm)
+	trans.out.releaseWindow(sm, err)
+	require.NoError(t, err)
+
+	// enquire_link takes the slot freed by response
+	require.NoError(t, store.Delete(ctx, sm.GetSequenceNumber()))
+	_, _, err = trans.out.enquireLink()
+	require.NoError(t, err)
+	size, err := store.Length(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, size)
+}